@@ -0,0 +1,123 @@
+package memcard
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// Action Replay and GameShark single saves (.psx) start with a 54 byte header holding the
+// file name and an ASCII version of the save title, followed by the save blocks:
+//
+//	0x00-0x14  file name (product code), null terminated
+//	0x15-0x35  save title in ASCII, null terminated
+//	0x36-...   save blocks
+const (
+	ActionReplayHeaderSize  = 54
+	actionReplayTitleOffset = 0x15
+)
+
+// actionReplayCodec reads and writes Action Replay/GameShark .psx saves.
+type actionReplayCodec struct{}
+
+func (actionReplayCodec) Format() SaveFormat {
+	return SaveFormatActionReplay
+}
+
+func (actionReplayCodec) Extensions() []string {
+	return []string{".psx"}
+}
+
+func (actionReplayCodec) Detect(data []byte) bool {
+	return len(data) > ActionReplayHeaderSize && (len(data)-ActionReplayHeaderSize)%BlockSize == 0
+}
+
+func (actionReplayCodec) Decode(data []byte, _ string) (*Save, error) {
+	return decodeActionReplay(data)
+}
+
+func (actionReplayCodec) Encode(save *Save) ([]byte, error) {
+	return encodeActionReplay(save)
+}
+
+// ExportActionReplaySave exports the file stored at blockIndex as Action Replay/GameShark .psx save.
+// blockIndex may point to any block of the file, the whole block chain is exported.
+func (mc *MemoryCard) ExportActionReplaySave(blockIndex int) ([]byte, error) {
+	save, err := mc.ExtractSave(blockIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeActionReplay(save)
+}
+
+// ImportActionReplaySave imports an Action Replay/GameShark .psx save into the first free blocks
+// of the card and returns the blocks it was written to.
+func (mc *MemoryCard) ImportActionReplaySave(r io.Reader) ([]int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	save, err := decodeActionReplay(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return mc.InsertSave(save)
+}
+
+// asciiTitle converts a save title to ASCII, full-width characters are narrowed
+// and characters without an ASCII counterpart are dropped.
+func asciiTitle(title string) string {
+	narrow := width.Narrow.String(title)
+	return string(bytes.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, []byte(narrow)))
+}
+
+func encodeActionReplay(save *Save) ([]byte, error) {
+	blocks, err := save.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, ActionReplayHeaderSize)
+	fileName := save.FileName()
+	copy(header[:actionReplayTitleOffset-1], fileName.String())
+
+	// Both fields keep their terminating null byte
+	title := asciiTitle(save.Title())
+	copy(header[actionReplayTitleOffset:ActionReplayHeaderSize-1], title)
+
+	return append(header, blocks...), nil
+}
+
+func decodeActionReplay(data []byte) (*Save, error) {
+	if len(data) <= ActionReplayHeaderSize {
+		return nil, fmt.Errorf("%w: not an Action Replay save", ErrInvalidSave)
+	}
+
+	name := data[:actionReplayTitleOffset]
+	if end := bytes.IndexByte(name, 0); end != -1 {
+		name = name[:end]
+	}
+
+	fileName, err := NewFileName(string(name))
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := decodeBlocks(data[ActionReplayHeaderSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSave(fileName, blocks), nil
+}
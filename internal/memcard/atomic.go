@@ -0,0 +1,92 @@
+package memcard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var ErrWriteVerificationFailed = errors.New("written file does not match")
+
+// defaultFileMode is used for files that do not exist yet.
+const defaultFileMode fs.FileMode = 0644
+
+// writeFileAtomic replaces the file with data without ever leaving a partially written file behind.
+// The data is written to a temporary file in the same directory, synced to disk, read back, compared
+// and checked with verify, then renamed over the file. The permissions of an existing file are kept,
+// symbolic links are followed so the file they point to is replaced.
+func writeFileAtomic(filePath string, data []byte, verify func(written []byte) error) (err error) {
+	if resolved, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = resolved
+	}
+
+	mode := defaultFileMode
+	if info, err := os.Stat(filePath); err == nil {
+		mode = info.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	dir, name := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+
+	temp, err := os.CreateTemp(dir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tempPath := temp.Name()
+	defer func() {
+		if err != nil {
+			temp.Close()
+			os.Remove(tempPath)
+		}
+	}()
+
+	if _, err = temp.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+	if err = temp.Chmod(mode); err != nil {
+		return err
+	}
+	if err = temp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", filePath, err)
+	}
+	if err = temp.Close(); err != nil {
+		return err
+	}
+
+	written, err := os.ReadFile(tempPath)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(written, data) {
+		return fmt.Errorf("%w: %s: read back %d bytes, expected %d bytes", ErrWriteVerificationFailed, filePath, len(written), len(data))
+	}
+	if verify != nil {
+		if err = verify(written); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrWriteVerificationFailed, filePath, err)
+		}
+	}
+
+	if err = os.Rename(tempPath, filePath); err != nil {
+		return err
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// syncDir syncs the directory, so a rename within it survives a crash.
+// Not every platform supports syncing directories, errors are ignored.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
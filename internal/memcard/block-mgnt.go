@@ -2,6 +2,7 @@ package memcard
 
 import (
 	"errors"
+	"slices"
 )
 
 var (
@@ -9,21 +10,38 @@ var (
 	ErrSourceBlockNotInUse  = errors.New("source block is not in use")
 	ErrNoFreeBlockAvailable = errors.New("no free block available on target memory card")
 	ErrTargetCardNil        = errors.New("target memory card is nil")
+	ErrNotEnoughFreeBlocks  = errors.New("not enough free blocks available on target memory card")
 )
 
-// FindFreeBlock finds the first available (free) block on the memory card.
+// FindFreeBlock finds the first available (free) block on the memory card, see FindFreeBlocks.
 // Returns the block index (0-14) and true if a free block was found, or -1 and false if no free block is available.
 func (mc *MemoryCard) FindFreeBlock() (int, bool) {
-	for i := 0; i < NumBlocks; i++ {
-		state := mc.DirectoryFrames[i].BlockAllocationState
-		if state == BlockAllocationStateFreeFresh ||
-			state == BlockAllocationStateFreeDeletedFirst ||
-			state == BlockAllocationStateFreeDeletedMiddle ||
-			state == BlockAllocationStateFreeDeletedLast {
-			return i, true
+	blocks, found := mc.FindFreeBlocks(1)
+	if !found {
+		return -1, false
+	}
+	return blocks[0], true
+}
+
+// FindFreeBlocks finds count free blocks on the memory card. Blocks that were never used are taken
+// first, blocks of deleted files only when needed, so deleted files stay recoverable as long as possible.
+// Returns the block indices in ascending order and true, or nil and false if the card has fewer free blocks.
+func (mc *MemoryCard) FindFreeBlocks(count int) ([]int, bool) {
+	blocks := []int{}
+	for _, fresh := range []bool{true, false} {
+		for i := 0; i < NumBlocks && len(blocks) < count; i++ {
+			state := mc.DirectoryFrames[i].BlockAllocationState
+			if state.IsFree() && (state == BlockAllocationStateFreeFresh) == fresh {
+				blocks = append(blocks, i)
+			}
 		}
 	}
-	return -1, false
+
+	if len(blocks) < count {
+		return nil, false
+	}
+	slices.Sort(blocks)
+	return blocks, true
 }
 
 // CountBlocks returns the total, used, and free block counts for the memory card.
@@ -33,11 +51,7 @@ func (mc *MemoryCard) CountBlocks() (total, used, free int) {
 	free = 0
 
 	for i := 0; i < NumBlocks; i++ {
-		state := mc.DirectoryFrames[i].BlockAllocationState
-		if state == BlockAllocationStateFreeFresh ||
-			state == BlockAllocationStateFreeDeletedFirst ||
-			state == BlockAllocationStateFreeDeletedMiddle ||
-			state == BlockAllocationStateFreeDeletedLast {
+		if mc.DirectoryFrames[i].BlockAllocationState.IsFree() {
 			free++
 		} else {
 			used++
@@ -47,65 +61,58 @@ func (mc *MemoryCard) CountBlocks() (total, used, free int) {
 	return total, used, free
 }
 
-// CopyBlockTo copies a block from the source memory card to the target memory card.
-// It finds a free block on the target card, copies the block data and directory frame,
-// and updates the allocation state to indicate it's a first-or-only block (0x51).
+// CopyBlockTo copies the file stored at blockIndex from the source memory card to the target memory card.
+// The whole block chain of the file is copied, blockIndex may point to any block of the file.
+// The blocks are written to free blocks of the target card (see FindFreeBlocks) and their directory frames
+// are rebuilt: allocation states, NextBlock links, file size, title frame block number and checksums.
+// If the target card does not have enough free blocks, neither card is modified.
 func (mc *MemoryCard) CopyBlockTo(blockIndex int, targetCard *MemoryCard) error {
 	if targetCard == nil {
 		return ErrTargetCardNil
 	}
 
-	if blockIndex < 0 || blockIndex >= NumBlocks {
-		return ErrInvalidBlockIndex
+	save, sourceChain, err := mc.extractSave(blockIndex)
+	if err != nil {
+		return err
 	}
 
-	// Verify source block is in use
-	sourceDirFrame := mc.DirectoryFrames[blockIndex]
-	if sourceDirFrame.BlockAllocationState == BlockAllocationStateFreeFresh ||
-		sourceDirFrame.BlockAllocationState == BlockAllocationStateFreeDeletedFirst ||
-		sourceDirFrame.BlockAllocationState == BlockAllocationStateFreeDeletedMiddle ||
-		sourceDirFrame.BlockAllocationState == BlockAllocationStateFreeDeletedLast {
-		return ErrSourceBlockNotInUse
+	targetChain, err := targetCard.InsertSave(save)
+	if err != nil {
+		return err
 	}
 
-	// Find a free block on the target card
-	targetBlockIndex, found := targetCard.FindFreeBlock()
-	if !found {
-		return ErrNoFreeBlockAvailable
+	// Comments belong to the blocks of the file, so they move along with it
+	for pos, targetIndex := range targetChain {
+		targetCard.copyComment(targetIndex, mc, sourceChain[pos])
 	}
 
-	// Copy the block data
-	targetCard.Blocks[targetBlockIndex] = mc.Blocks[blockIndex]
-
-	// Copy the directory frame, but update it for the target
-	targetCard.DirectoryFrames[targetBlockIndex] = sourceDirFrame
-
-	// Update the allocation state to indicate it's a first-or-only block
-	// When copying a single block, it becomes a standalone file
-	targetCard.DirectoryFrames[targetBlockIndex].BlockAllocationState = BlockAllocationStateInUseFirstOnlyBlock
-
-	// Update NextBlock to FFFFh (indicates last-or-only block)
-	targetCard.DirectoryFrames[targetBlockIndex].NextBlock = 0xFFFF
-
-	// Update file size to 0x2000 (8KB) for a single block file
-	targetCard.DirectoryFrames[targetBlockIndex].FileSize = BlockSize
-
-	// Update the block number in the title frame to match the new block index
-	targetCard.Blocks[targetBlockIndex].TitleFrame.BlockNumber = byte(targetBlockIndex + 1)
-
-	// Recalculate and update the checksum for the directory frame
-	targetCard.DirectoryFrames[targetBlockIndex].Checksum = calculateDirectoryFrameChecksum(&targetCard.DirectoryFrames[targetBlockIndex])
-
 	return nil
 }
 
+// DeleteBlockFrom deletes the file stored at blockIndex the same way the PSX BIOS does.
+// blockIndex may point to any block of the file, the whole block chain is deleted.
+// The blocks are marked as deleted first, middle and last block (0xA1, 0xA2, 0xA3) and their
+// directory frame checksums are recalculated. The file name, NextBlock links and block data are
+// kept in place, so the file can be recovered until its blocks are reused.
 func (mc *MemoryCard) DeleteBlockFrom(blockIndex int) error {
-	// TODO: Implement block deletion logic
+	if blockIndex < 0 || blockIndex >= NumBlocks {
+		return ErrInvalidBlockIndex
+	}
+
+	if !mc.DirectoryFrames[blockIndex].BlockAllocationState.IsInUse() {
+		return ErrSourceBlockNotInUse
+	}
 
-	mc.DirectoryFrames[blockIndex].BlockAllocationState = BlockAllocationStateFreeDeletedFirst
-	mc.DirectoryFrames[blockIndex].FileName = NewEmptyFileName()
+	chain, err := mc.FileChain(blockIndex)
+	if err != nil {
+		return err
+	}
 
-	mc.Blocks[blockIndex].CleanBlock()
+	mc.linkChain(chain,
+		BlockAllocationStateFreeDeletedFirst,
+		BlockAllocationStateFreeDeletedMiddle,
+		BlockAllocationStateFreeDeletedLast,
+	)
 
 	return nil
 }
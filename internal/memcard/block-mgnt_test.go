@@ -0,0 +1,249 @@
+package memcard
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// writeTestFile stores a file spanning the given blocks on the card. Each block
+// gets its chain position as data marker, so moved blocks can be recognized.
+func writeTestFile(t *testing.T, card *MemoryCard, name string, blocks ...int) {
+	t.Helper()
+
+	for pos, idx := range blocks {
+		card.Blocks[idx].CleanBlock()
+		card.Blocks[idx].Data[0][0] = byte(pos + 1)
+		card.DirectoryFrames[idx].FileName = NewEmptyFileName()
+		card.DirectoryFrames[idx].FileSize = 0
+	}
+
+	first := blocks[0]
+	card.Blocks[first].TitleFrame.ID = [2]byte{'S', 'C'}
+	card.Blocks[first].TitleFrame.IconDisplayFlag = IconDisplayFlagOneFrameIcon
+	card.Blocks[first].TitleFrame.BlockNumber = byte(first + 1)
+	copy(card.DirectoryFrames[first].FileName[:], name)
+	card.DirectoryFrames[first].FileSize = uint32(len(blocks) * BlockSize)
+
+	card.linkChain(blocks,
+		BlockAllocationStateInUseFirstOnlyBlock,
+		BlockAllocationStateInUseMiddleBlock,
+		BlockAllocationStateInUseLastBlock,
+	)
+}
+
+// assertChecksums fails the test if any directory frame has a wrong checksum.
+func assertChecksums(t *testing.T, card *MemoryCard) {
+	t.Helper()
+
+	for i := range NumBlocks {
+		df := &card.DirectoryFrames[i]
+		if df.Checksum != calculateDirectoryFrameChecksum(df) {
+			t.Errorf("Expected valid checksum for directory frame %d", i)
+		}
+	}
+}
+
+func TestCopyBlockTo_MultiBlockFile(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BASLUS-00892FF7", 4, 9, 2)
+
+	target := NewFormattedMemoryCard()
+	writeTestFile(t, target, "BASLUS-00001AIRCOMB", 1)
+
+	// Copying from a middle block copies the whole file
+	if err := source.CopyBlockTo(9, target); err != nil {
+		t.Fatalf("Error copying file: %v", err)
+	}
+
+	chain, err := target.FileChain(0)
+	if err != nil {
+		t.Fatalf("Error reading copied chain: %v", err)
+	}
+
+	expectedChain := []int{0, 2, 3}
+	if len(chain) != len(expectedChain) {
+		t.Fatalf("Expected chain %v, but got: %v", expectedChain, chain)
+	}
+	for pos, idx := range expectedChain {
+		if chain[pos] != idx {
+			t.Fatalf("Expected chain %v, but got: %v", expectedChain, chain)
+		}
+		if marker := target.Blocks[idx].Data[0][0]; marker != byte(pos+1) {
+			t.Errorf("Expected block %d to hold chain position %d, but got: %d", idx, pos+1, marker)
+		}
+	}
+
+	first := target.DirectoryFrames[0]
+	if first.FileSize != 3*BlockSize {
+		t.Errorf("Expected file size %d, but got: %d", 3*BlockSize, first.FileSize)
+	}
+	if first.FileName != source.DirectoryFrames[4].FileName {
+		t.Errorf("Expected file name to be copied, but got: %q", first.FileName[:])
+	}
+	if target.Blocks[0].TitleFrame.BlockNumber != 1 {
+		t.Errorf("Expected title frame block number 1, but got: %d", target.Blocks[0].TitleFrame.BlockNumber)
+	}
+	if target.DirectoryFrames[3].FileSize != 0 {
+		t.Errorf("Expected last block file size 0, but got: %d", target.DirectoryFrames[3].FileSize)
+	}
+	assertChecksums(t, target)
+}
+
+func TestCopyBlockTo_NotEnoughFreeBlocks(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BASLUS-00892FF7", 0, 1, 2)
+
+	target := NewFormattedMemoryCard()
+	writeTestFile(t, target, "BASLUS-00001AIRCOMB", 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+	before := *target
+
+	err := source.CopyBlockTo(0, target)
+	if !errors.Is(err, ErrNotEnoughFreeBlocks) {
+		t.Fatalf("Expected ErrNotEnoughFreeBlocks, but got: %v", err)
+	}
+	if *target != before {
+		t.Errorf("Expected target card to be unchanged after a failed copy")
+	}
+}
+
+func TestCopyBlockTo_KeepsDeletedFiles(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BASLUS-00892FF7", 0, 1)
+
+	target := NewFormattedMemoryCard()
+	writeTestFile(t, target, "BASLUS-00001AIRCOMB", 0, 1)
+	if err := target.DeleteBlockFrom(0); err != nil {
+		t.Fatalf("Error deleting file: %v", err)
+	}
+
+	// Never used blocks are taken before the blocks of the deleted file
+	if err := source.CopyBlockTo(0, target); err != nil {
+		t.Fatalf("Error copying file: %v", err)
+	}
+	if chain, err := target.FileChain(2); err != nil || !slices.Equal(chain, []int{2, 3}) {
+		t.Errorf("Expected copy in blocks [2 3], but got: %v (%v)", chain, err)
+	}
+	if err := target.RestoreDeletedFile(0); err != nil {
+		t.Errorf("Expected deleted file to stay recoverable, but got: %v", err)
+	}
+
+	// Blocks of deleted files are used when no other blocks are left
+	target = NewFormattedMemoryCard()
+	writeTestFile(t, target, "BASLUS-00001AIRCOMB", 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+	if err := target.DeleteBlockFrom(0); err != nil {
+		t.Fatalf("Error deleting file: %v", err)
+	}
+	if blocks, found := target.FindFreeBlocks(3); !found || !slices.Equal(blocks, []int{0, 13, 14}) {
+		t.Errorf("Expected free blocks [0 13 14], but got: %v", blocks)
+	}
+}
+
+func TestInsertSave_ClearsComments(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BASLUS-00892FF7", 0, 1)
+	save, err := source.ExtractSave(0)
+	if err != nil {
+		t.Fatalf("Error extracting save: %v", err)
+	}
+
+	// Comments are kept for free blocks, e.g. of a deleted file of a DexDrive image
+	target := NewFormattedMemoryCard()
+	for _, idx := range []int{0, 1, 2} {
+		if err := target.SetComment(idx, "old comment"); err != nil {
+			t.Fatalf("Error setting comment: %v", err)
+		}
+	}
+
+	chain, err := target.InsertSave(save)
+	if err != nil {
+		t.Fatalf("Error inserting save: %v", err)
+	}
+	for _, idx := range chain {
+		if comment := target.Comment(idx); comment != "" {
+			t.Errorf("Expected no comment for block %d, but got: %q", idx, comment)
+		}
+	}
+	if comment := target.Comment(2); comment != "old comment" {
+		t.Errorf("Expected comment of an unused block to be kept, but got: %q", comment)
+	}
+}
+
+func TestCopyBlockTo_BrokenChain(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BASLUS-00892FF7", 0, 1)
+	source.DirectoryFrames[1].NextBlock = 0
+
+	err := source.CopyBlockTo(0, NewFormattedMemoryCard())
+	if !errors.Is(err, ErrBrokenBlockChain) {
+		t.Fatalf("Expected ErrBrokenBlockChain, but got: %v", err)
+	}
+}
+
+func TestDeleteBlockFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		blocks     []int
+		blockIndex int
+		expected   []BlockAllocationState
+	}{
+		{
+			name:       "single block file",
+			blocks:     []int{3},
+			blockIndex: 3,
+			expected:   []BlockAllocationState{BlockAllocationStateFreeDeletedFirst},
+		},
+		{
+			name:       "multi block file from first block",
+			blocks:     []int{2, 7, 5},
+			blockIndex: 2,
+			expected: []BlockAllocationState{
+				BlockAllocationStateFreeDeletedFirst,
+				BlockAllocationStateFreeDeletedMiddle,
+				BlockAllocationStateFreeDeletedLast,
+			},
+		},
+		{
+			name:       "multi block file from last block",
+			blocks:     []int{2, 7, 5},
+			blockIndex: 5,
+			expected: []BlockAllocationState{
+				BlockAllocationStateFreeDeletedFirst,
+				BlockAllocationStateFreeDeletedMiddle,
+				BlockAllocationStateFreeDeletedLast,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := NewFormattedMemoryCard()
+			writeTestFile(t, card, "BASLUS-00892FF7", tt.blocks...)
+
+			if err := card.DeleteBlockFrom(tt.blockIndex); err != nil {
+				t.Fatalf("Error deleting file: %v", err)
+			}
+
+			for pos, idx := range tt.blocks {
+				if state := card.DirectoryFrames[idx].BlockAllocationState; state != tt.expected[pos] {
+					t.Errorf("Expected block %d to have state 0x%02X, but got: 0x%02X", idx, uint32(tt.expected[pos]), uint32(state))
+				}
+				if marker := card.Blocks[idx].Data[0][0]; marker != byte(pos+1) {
+					t.Errorf("Expected block %d data to be kept", idx)
+				}
+			}
+			if card.DirectoryFrames[tt.blocks[0]].FileName[0] != 'B' {
+				t.Errorf("Expected file name to be kept")
+			}
+			assertChecksums(t, card)
+		})
+	}
+}
+
+func TestDeleteBlockFrom_FreeBlock(t *testing.T) {
+	card := NewFormattedMemoryCard()
+
+	if err := card.DeleteBlockFrom(0); !errors.Is(err, ErrSourceBlockNotInUse) {
+		t.Fatalf("Expected ErrSourceBlockNotInUse, but got: %v", err)
+	}
+}
@@ -15,6 +15,9 @@ type BlockItem struct {
 	Title       string
 	Animation   animatedsprite.Animation
 	BlockNumber uint8
+	Remapped    bool      // some frames of the save are read from replacement frames
+	ProductCode string    // canonical product code of the file name, "" for homebrew names
+	Game        *GameInfo // game of the card's game database, nil if the product code is unknown
 }
 
 func (mc *MemoryCard) GetBlock(blockNumber int) (*BlockItem, error) {
@@ -23,7 +26,7 @@ func (mc *MemoryCard) GetBlock(blockNumber int) (*BlockItem, error) {
 	}
 
 	df := mc.DirectoryFrames[blockNumber]
-	block := mc.Blocks[blockNumber]
+	block := mc.readBlock(blockNumber)
 
 	if df.BlockAllocationState == BlockAllocationStateFreeFresh ||
 		df.BlockAllocationState == BlockAllocationStateFreeDeletedFirst ||
@@ -33,36 +36,31 @@ func (mc *MemoryCard) GetBlock(blockNumber int) (*BlockItem, error) {
 	}
 
 	frames := []image.Image{}
-	for idx, f := range block.IconFrames {
-
-		switch block.TitleFrame.IconDisplayFlag {
-		case IconDisplayFlagOneFrameIcon:
-			if idx > 0 {
-				continue
-			}
-		case IconDisplayFlagTwoFrameIcon:
-			if idx > 1 {
-				continue
-			}
-		case IconDisplayFlagThreeFrameIcon:
-			if idx > 2 {
-				continue
-			}
-
-		}
-		frames = append(frames, f.ToImage(block.TitleFrame.IconColorPalette))
+	for _, frame := range iconImages(&block, STPOpaque) {
+		frames = append(frames, frame)
 	}
 
+	// Animate with the BIOS frame delays, like exported GIFs
+	animation := animatedsprite.NewAnimation(frames, IconFrameDelay(len(frames)))
+
 	item := &BlockItem{
 		Title:     block.TitleFrame.Title.String(),
-		Animation: animatedsprite.NewAnimation(frames),
+		Animation: animation,
 
 		BlockNumber: uint8(blockNumber),
+		Remapped:    mc.IsRemapped(blockNumber),
+		ProductCode: df.FileName.GameCode(),
+	}
+
+	if game, found := mc.GameDatabase().Lookup(item.ProductCode); found {
+		item.Game = &game
 	}
 
 	return item, nil
 }
 
+// ListBlocks returns the blocks that are in use, free blocks are skipped. Use ListSlots to list
+// every physical block.
 func (mc *MemoryCard) ListBlocks() ([]BlockItem, error) {
 	var items []BlockItem
 
@@ -0,0 +1,141 @@
+package memcard
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Files larger than one block are stored as a chain of blocks. The first block
+// holds the file name, size and title frame, every block points to the next one
+// through DirectoryFrame.NextBlock (0..14) and the last block ends the chain
+// with NoNextBlock.
+
+var (
+	ErrBrokenBlockChain = errors.New("broken block chain")
+	ErrOrphanBlock      = errors.New("block does not belong to any file")
+	ErrBlockChainLoop   = errors.New("block chain loops")
+	ErrDanglingLink     = errors.New("block links outside of the card")
+)
+
+// walkChain follows the NextBlock links starting at first and returns the visited
+// block indices. It only checks that the links stay on the card and do not loop,
+// the allocation states of the visited blocks are left to the caller.
+func (mc *MemoryCard) walkChain(first int) ([]int, error) {
+	if first < 0 || first >= NumBlocks {
+		return nil, ErrInvalidBlockIndex
+	}
+
+	var visited [NumBlocks]bool
+	chain := []int{}
+
+	for current := first; ; {
+		if visited[current] {
+			return chain, fmt.Errorf("%w: %w: block %d links back to block %d", ErrBrokenBlockChain, ErrBlockChainLoop, chain[len(chain)-1], current)
+		}
+		visited[current] = true
+		chain = append(chain, current)
+
+		next := mc.DirectoryFrames[current].NextBlock
+		if next == NoNextBlock {
+			return chain, nil
+		}
+		if int(next) >= NumBlocks {
+			return chain, fmt.Errorf("%w: %w: block %d links to invalid block %d", ErrBrokenBlockChain, ErrDanglingLink, current, next)
+		}
+		current = int(next)
+	}
+}
+
+// checkChainStates verifies that the blocks of a chain carry the expected
+// first, middle and last allocation states.
+func (mc *MemoryCard) checkChainStates(chain []int, first, middle, last BlockAllocationState) error {
+	for pos, idx := range chain {
+		expected := chainState(pos, len(chain), first, middle, last)
+		if state := mc.DirectoryFrames[idx].BlockAllocationState; state != expected {
+			return fmt.Errorf("%w: block %d has state 0x%02X, expected 0x%02X", ErrBrokenBlockChain, idx, uint32(state), uint32(expected))
+		}
+	}
+	return nil
+}
+
+// FirstBlockOf returns the index of the first block of the file the given block
+// belongs to. Middle and last blocks are resolved by searching the chains of all
+// files on the card.
+func (mc *MemoryCard) FirstBlockOf(blockIndex int) (int, error) {
+	if blockIndex < 0 || blockIndex >= NumBlocks {
+		return -1, ErrInvalidBlockIndex
+	}
+
+	switch mc.DirectoryFrames[blockIndex].BlockAllocationState {
+	case BlockAllocationStateInUseFirstOnlyBlock:
+		return blockIndex, nil
+	case BlockAllocationStateInUseMiddleBlock, BlockAllocationStateInUseLastBlock:
+	default:
+		return -1, ErrSourceBlockNotInUse
+	}
+
+	for i := range NumBlocks {
+		if mc.DirectoryFrames[i].BlockAllocationState != BlockAllocationStateInUseFirstOnlyBlock {
+			continue
+		}
+
+		chain, _ := mc.walkChain(i)
+		for _, idx := range chain {
+			if idx == blockIndex {
+				return i, nil
+			}
+		}
+	}
+
+	return -1, fmt.Errorf("%w: block %d", ErrOrphanBlock, blockIndex)
+}
+
+// FileChain returns the block indices of the file the given block belongs to,
+// in chain order starting with the file's first block.
+func (mc *MemoryCard) FileChain(blockIndex int) ([]int, error) {
+	first, err := mc.FirstBlockOf(blockIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := mc.walkChain(first)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mc.checkChainStates(chain,
+		BlockAllocationStateInUseFirstOnlyBlock,
+		BlockAllocationStateInUseMiddleBlock,
+		BlockAllocationStateInUseLastBlock,
+	); err != nil {
+		return nil, err
+	}
+
+	return chain, nil
+}
+
+// chainState returns the allocation state, or any other value that depends on the
+// position, for the block at position pos of a chain with the given length.
+func chainState[T any](pos, length int, first, middle, last T) T {
+	switch {
+	case pos == 0:
+		return first
+	case pos == length-1:
+		return last
+	}
+	return middle
+}
+
+// linkChain rewrites the allocation states and NextBlock links of the directory
+// frames so the given blocks form a single chain, and updates their checksums.
+func (mc *MemoryCard) linkChain(chain []int, first, middle, last BlockAllocationState) {
+	for pos, idx := range chain {
+		df := &mc.DirectoryFrames[idx]
+		df.BlockAllocationState = chainState(pos, len(chain), first, middle, last)
+		df.NextBlock = NoNextBlock
+		if pos < len(chain)-1 {
+			df.NextBlock = uint16(chain[pos+1])
+		}
+		df.Checksum = calculateDirectoryFrameChecksum(df)
+	}
+}
@@ -0,0 +1,287 @@
+package memcard
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CardCodec reads and writes whole memory card images of one file format.
+type CardCodec interface {
+	// Format identifies the file format of the codec.
+	Format() CardFormat
+	// Extensions lists the file extensions of the format, the first one is used for new files.
+	Extensions() []string
+	// Detect reports whether the data is an image of the format, judging by magic bytes and size.
+	Detect(data []byte) bool
+	// Decode decodes an image of the format.
+	Decode(data []byte) (*MemoryCard, error)
+	// Encode encodes a memory card as image of the format.
+	Encode(card *MemoryCard) ([]byte, error)
+}
+
+// SaveCodec reads and writes single saves of one file format.
+type SaveCodec interface {
+	// Format identifies the file format of the codec.
+	Format() SaveFormat
+	// Extensions lists the file extensions of the format, the first one is used for exported files.
+	Extensions() []string
+	// Detect reports whether the data is a save of the format, judging by magic bytes and size.
+	Detect(data []byte) bool
+	// Decode decodes a save of the format, fileName is the name of the file the save was read from.
+	Decode(data []byte, fileName string) (*Save, error)
+	// Encode encodes a save in the format.
+	Encode(save *Save) ([]byte, error)
+}
+
+// CodecRegistry holds the card and save codecs used to detect, read and write file formats.
+type CodecRegistry struct {
+	cardCodecs []CardCodec
+	saveCodecs []SaveCodec
+	lock       sync.RWMutex
+}
+
+var defaultCodecRegistry = NewCodecRegistry()
+
+// DefaultCodecRegistry returns the registry used by Open, MemoryCard.Write and the single save functions.
+func DefaultCodecRegistry() *CodecRegistry {
+	return defaultCodecRegistry
+}
+
+// NewCodecRegistry creates a registry with all built-in codecs.
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{
+		// Formats with magic bytes come first, so they win over formats only detected by their size
+		cardCodecs: []CardCodec{
+			dexDriveCodec{},
+			vgsCodec,
+			vmpCodec,
+			rawCardCodec{},
+			prefixedCodec,
+		},
+		saveCodecs: []SaveCodec{
+			psvCodec{},
+			mcsCodec{},
+			actionReplayCodec{},
+			rawSaveCodec{},
+		},
+	}
+}
+
+// RegisterCardCodec adds a card codec. It takes precedence over the codecs registered before.
+func (r *CodecRegistry) RegisterCardCodec(codec CardCodec) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.cardCodecs = append([]CardCodec{codec}, r.cardCodecs...)
+}
+
+// RegisterSaveCodec adds a save codec. It takes precedence over the codecs registered before.
+func (r *CodecRegistry) RegisterSaveCodec(codec SaveCodec) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.saveCodecs = append([]SaveCodec{codec}, r.saveCodecs...)
+}
+
+// CardCodecs returns all registered card codecs.
+func (r *CodecRegistry) CardCodecs() []CardCodec {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return slices.Clone(r.cardCodecs)
+}
+
+// SaveCodecs returns all registered save codecs.
+func (r *CodecRegistry) SaveCodecs() []SaveCodec {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return slices.Clone(r.saveCodecs)
+}
+
+// CardCodec returns the card codec for a format.
+func (r *CodecRegistry) CardCodec(format CardFormat) (CardCodec, error) {
+	for _, codec := range r.CardCodecs() {
+		if codec.Format() == format {
+			return codec, nil
+		}
+	}
+	return nil, ErrUnknownCardFormat
+}
+
+// SaveCodec returns the save codec for a format.
+func (r *CodecRegistry) SaveCodec(format SaveFormat) (SaveCodec, error) {
+	for _, codec := range r.SaveCodecs() {
+		if codec.Format() == format {
+			return codec, nil
+		}
+	}
+	return nil, ErrUnknownSaveFormat
+}
+
+// CardCodecForFileName returns the card codec for the extension of the file name.
+func (r *CodecRegistry) CardCodecForFileName(fileName string) (CardCodec, error) {
+	if codec, found := codecForExtension(r.CardCodecs(), fileName); found {
+		return codec, nil
+	}
+	return nil, ErrUnknownCardFormat
+}
+
+// SaveCodecForFileName returns the save codec for the extension of the file name.
+func (r *CodecRegistry) SaveCodecForFileName(fileName string) (SaveCodec, error) {
+	if codec, found := codecForExtension(r.SaveCodecs(), fileName); found {
+		return codec, nil
+	}
+	return nil, ErrUnknownSaveFormat
+}
+
+// DetectCardCodec detects the format of a memory card image by its magic bytes and size.
+// If several formats match, the one matching the extension of the file name is used.
+func (r *CodecRegistry) DetectCardCodec(data []byte, fileName string) (CardCodec, error) {
+	if codec, found := detectCodec(r.CardCodecs(), data, fileName); found {
+		return codec, nil
+	}
+	return nil, ErrInvalidMemoryCardSize
+}
+
+// DetectSaveCodec detects the format of a single save by its magic bytes and size.
+// If several formats match, the one matching the extension of the file name is used.
+func (r *CodecRegistry) DetectSaveCodec(data []byte, fileName string) (SaveCodec, error) {
+	if codec, found := detectCodec(r.SaveCodecs(), data, fileName); found {
+		return codec, nil
+	}
+	return nil, ErrUnknownSaveFormat
+}
+
+// Open reads a memory card image in any registered format.
+// The card remembers the codec it was read with, so it is written back in the same format.
+func (r *CodecRegistry) Open(filePath string) (*MemoryCard, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return r.Read(file, filePath)
+}
+
+// Read reads a memory card image in any registered format from reader.
+// fileName is only used to tell formats apart by their extension and may be empty.
+func (r *CodecRegistry) Read(reader io.Reader, fileName string) (*MemoryCard, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.DecodeCard(data, fileName)
+}
+
+// DecodeCard decodes a memory card image in any registered format.
+func (r *CodecRegistry) DecodeCard(data []byte, fileName string) (*MemoryCard, error) {
+	if len(data) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	codec, err := r.DetectCardCodec(data, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := codec.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	card.metadata().codec = codec
+	return card, nil
+}
+
+// ExportSave exports the file stored at blockIndex in the given single save format.
+// It returns the data and a suggested file name: the file name of the save with the extension of the format.
+func (r *CodecRegistry) ExportSave(card *MemoryCard, blockIndex int, format SaveFormat) (string, []byte, error) {
+	codec, err := r.SaveCodec(format)
+	if err != nil {
+		return "", nil, err
+	}
+
+	save, err := card.ExtractSave(blockIndex)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := codec.Encode(save)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fileName := save.FileName()
+	return fileName.String() + codec.Extensions()[0], data, nil
+}
+
+// ImportSave imports a single save in any registered format into the first free blocks of the card
+// and returns the blocks it was written to.
+func (r *CodecRegistry) ImportSave(card *MemoryCard, fileName string, reader io.Reader) ([]int, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := r.DetectSaveCodec(data, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	save, err := codec.Decode(data, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return card.InsertSave(save)
+}
+
+// formatCodec is implemented by card and save codecs.
+type formatCodec interface {
+	Extensions() []string
+	Detect(data []byte) bool
+}
+
+// hasExtension reports whether the file name has one of the extensions of the codec.
+func hasExtension(codec formatCodec, fileName string) bool {
+	extension := strings.ToLower(filepath.Ext(fileName))
+	return extension != "" && slices.Contains(codec.Extensions(), extension)
+}
+
+func codecForExtension[T formatCodec](codecs []T, fileName string) (T, bool) {
+	for _, codec := range codecs {
+		if hasExtension(codec, fileName) {
+			return codec, true
+		}
+	}
+
+	var none T
+	return none, false
+}
+
+func detectCodec[T formatCodec](codecs []T, data []byte, fileName string) (T, bool) {
+	var detected []T
+	for _, codec := range codecs {
+		if codec.Detect(data) {
+			detected = append(detected, codec)
+		}
+	}
+
+	if len(detected) == 0 {
+		var none T
+		return none, false
+	}
+
+	if codec, found := codecForExtension(detected, fileName); found {
+		return codec, true
+	}
+	return detected[0], true
+}
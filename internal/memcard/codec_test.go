@@ -0,0 +1,166 @@
+package memcard
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testCardCodec stores cards behind a "TEST" magic.
+type testCardCodec struct{}
+
+func (testCardCodec) Format() CardFormat   { return "test" }
+func (testCardCodec) Extensions() []string { return []string{".tst"} }
+
+func (testCardCodec) Detect(data []byte) bool {
+	return len(data) == 4+MemoryCardTotalSize && bytes.HasPrefix(data, []byte("TEST"))
+}
+
+func (testCardCodec) Decode(data []byte) (*MemoryCard, error) {
+	return readRawImage(data[4:])
+}
+
+func (testCardCodec) Encode(card *MemoryCard) ([]byte, error) {
+	raw, err := card.encodeRawImage()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("TEST"), raw...), nil
+}
+
+func TestCodecRegistry_DetectCardCodec(t *testing.T) {
+	raw, err := os.ReadFile("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error reading card: %v", err)
+	}
+	vmp := append(newVMPHeader(), raw...)
+
+	registry := NewCodecRegistry()
+	registry.RegisterCardCodec(testCardCodec{})
+
+	tests := []struct {
+		name     string
+		data     []byte
+		fileName string
+		expected CardFormat
+	}{
+		{name: "raw", data: raw, fileName: "card.srm", expected: CardFormatRaw},
+		{name: "raw with misleading extension", data: raw, fileName: "card.gme", expected: CardFormatRaw},
+		{name: "vmp", data: vmp, fileName: "SCEVMC0.VMP", expected: CardFormatVMP},
+		{name: "vmp without extension", data: vmp, fileName: "card", expected: CardFormatVMP},
+		{name: "vmp as unknown header", data: vmp, fileName: "card.mem", expected: CardFormatVMP},
+		{name: "registered codec", data: append([]byte("TEST"), raw...), fileName: "card.bin", expected: "test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := registry.DetectCardCodec(tt.data, tt.fileName)
+			if err != nil {
+				t.Fatalf("Error detecting format: %v", err)
+			}
+			if codec.Format() != tt.expected {
+				t.Errorf("Expected format %s, but got: %s", tt.expected, codec.Format())
+			}
+		})
+	}
+
+	if _, err := registry.DetectCardCodec(raw[:BlockSize], "card.mcr"); err != ErrInvalidMemoryCardSize {
+		t.Errorf("Expected ErrInvalidMemoryCardSize, but got: %v", err)
+	}
+}
+
+func TestCodecRegistry_OpenKeepsCodec(t *testing.T) {
+	raw, err := os.ReadFile("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error reading card: %v", err)
+	}
+
+	registry := NewCodecRegistry()
+	registry.RegisterCardCodec(testCardCodec{})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "card.bin")
+	image := append([]byte("TEST"), raw...)
+	if err := os.WriteFile(path, image, 0600); err != nil {
+		t.Fatalf("Error writing image: %v", err)
+	}
+
+	card, err := registry.Open(path)
+	if err != nil {
+		t.Fatalf("Error opening image: %v", err)
+	}
+	if card.Format() != "test" {
+		t.Fatalf("Expected format test, but got: %s", card.Format())
+	}
+
+	written := filepath.Join(dir, "written.mcr")
+	if err := card.Write(written); err != nil {
+		t.Fatalf("Error writing card: %v", err)
+	}
+
+	data, err := os.ReadFile(written)
+	if err != nil {
+		t.Fatalf("Error reading written card: %v", err)
+	}
+	if !bytes.Equal(data, image) {
+		t.Errorf("Expected card to be written with the codec it was loaded with")
+	}
+}
+
+func TestWrite_NewCardUsesExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "card.gme")
+	if err := NewFormattedMemoryCard().Write(path); err != nil {
+		t.Fatalf("Error writing card: %v", err)
+	}
+
+	card, err := Open(path)
+	if err != nil {
+		t.Fatalf("Error opening card: %v", err)
+	}
+	if card.Format() != CardFormatDexDrive {
+		t.Errorf("Expected format %s, but got: %s", CardFormatDexDrive, card.Format())
+	}
+}
+
+func TestWrite_NewCardKeepsNoFormat(t *testing.T) {
+	dir := t.TempDir()
+	card := NewFormattedMemoryCard()
+	if err := card.Write(filepath.Join(dir, "a.gme")); err != nil {
+		t.Fatalf("Error writing card: %v", err)
+	}
+	if card.Codec() != nil {
+		t.Errorf("Expected card created in memory to keep no format, but got: %s", card.Format())
+	}
+
+	// The next write picks the format of its own extension
+	path := filepath.Join(dir, "b.mcr")
+	if err := card.Write(path); err != nil {
+		t.Fatalf("Error writing card: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || len(data) != MemoryCardTotalSize {
+		t.Errorf("Expected raw image of %d bytes, but got %d bytes (%v)", MemoryCardTotalSize, len(data), err)
+	}
+}
+
+func TestImportSaveAs_DetectsContent(t *testing.T) {
+	source, err := Open("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error opening card: %v", err)
+	}
+
+	_, data, err := source.ExportSaveAs(1, SaveFormatPSV)
+	if err != nil {
+		t.Fatalf("Error exporting save: %v", err)
+	}
+
+	// A PSV save renamed to .mcs is still imported as PSV
+	target := NewFormattedMemoryCard()
+	chain, err := target.ImportSaveAs("save.mcs", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error importing save: %v", err)
+	}
+	if target.DirectoryFrames[chain[0]].FileName != source.DirectoryFrames[1].FileName {
+		t.Errorf("Expected file name %q, but got: %q", source.DirectoryFrames[1].FileName.String(), target.DirectoryFrames[chain[0]].FileName.String())
+	}
+}
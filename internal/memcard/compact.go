@@ -0,0 +1,87 @@
+package memcard
+
+import (
+	"fmt"
+)
+
+// Files returns the block chains of all files on the card, ordered by the index of their first block.
+func (mc *MemoryCard) Files() ([][]int, error) {
+	files := [][]int{}
+	owned := 0
+
+	for i := range NumBlocks {
+		if mc.DirectoryFrames[i].BlockAllocationState != BlockAllocationStateInUseFirstOnlyBlock {
+			continue
+		}
+
+		chain, err := mc.FileChain(i)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, chain)
+		owned += len(chain)
+	}
+
+	_, used, _ := mc.CountBlocks()
+	if owned != used {
+		return nil, fmt.Errorf("%w: %d used blocks are not part of a file", ErrOrphanBlock, used-owned)
+	}
+
+	return files, nil
+}
+
+// Compact moves the files on the card so the blocks of each file are contiguous and in chain order.
+// Files keep their order by first block and all free blocks are packed at the end of the card as
+// freshly formatted blocks, which means deleted files can no longer be restored afterwards.
+// NextBlock links, title frame block numbers and checksums are updated along the way.
+// Cards with broken block chains are rejected unchanged, they need to be repaired first.
+func (mc *MemoryCard) Compact() error {
+	files, err := mc.Files()
+	if err != nil {
+		return err
+	}
+
+	var frames [NumBlocks]DirectoryFrame
+	var blocks [NumBlocks]Block
+	var comments [NumBlocks]string
+	targetChains := [][]int{}
+
+	slot := 0
+	for _, chain := range files {
+		targetChain := []int{}
+		for _, sourceIndex := range chain {
+			frames[slot] = mc.DirectoryFrames[sourceIndex]
+			blocks[slot] = mc.readBlock(sourceIndex)
+			comments[slot] = mc.Comment(sourceIndex)
+			targetChain = append(targetChain, slot)
+			slot++
+		}
+
+		// Only the first block has a title frame, the following blocks hold raw save data
+		blocks[targetChain[0]].TitleFrame.BlockNumber = byte(targetChain[0] + 1)
+		targetChains = append(targetChains, targetChain)
+	}
+
+	for ; slot < NumBlocks; slot++ {
+		frames[slot] = newFreeDirectoryFrame()
+	}
+
+	mc.DirectoryFrames = frames
+	for i := range blocks {
+		mc.writeBlock(i, blocks[i])
+	}
+	if mc.meta != nil {
+		mc.meta.comments = comments
+	}
+
+	for _, targetChain := range targetChains {
+		mc.linkChain(targetChain,
+			BlockAllocationStateInUseFirstOnlyBlock,
+			BlockAllocationStateInUseMiddleBlock,
+			BlockAllocationStateInUseLastBlock,
+		)
+	}
+
+	return nil
+}
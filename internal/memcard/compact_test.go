@@ -0,0 +1,57 @@
+package memcard
+
+import (
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 6, 2, 11)
+	writeTestFile(t, card, "BASLUS-00001AIRCOMB", 3)
+	writeTestFile(t, card, "BASLUS-00707SILENT", 9, 8)
+	writeTestFile(t, card, "BASLUS-00594DELETED", 0)
+	if err := card.DeleteBlockFrom(0); err != nil {
+		t.Fatalf("Error deleting file: %v", err)
+	}
+
+	if err := card.Compact(); err != nil {
+		t.Fatalf("Error compacting card: %v", err)
+	}
+
+	files, err := card.Files()
+	if err != nil {
+		t.Fatalf("Error reading files: %v", err)
+	}
+
+	// Files keep their order by first block: AIRCOMB (3), FF7 (6), SILENT (9)
+	expected := [][]int{{0}, {1, 2, 3}, {4, 5}}
+	if len(files) != len(expected) {
+		t.Fatalf("Expected files %v, but got: %v", expected, files)
+	}
+	for i, chain := range expected {
+		if len(files[i]) != len(chain) {
+			t.Fatalf("Expected files %v, but got: %v", expected, files)
+		}
+		for pos, idx := range chain {
+			if files[i][pos] != idx {
+				t.Fatalf("Expected files %v, but got: %v", expected, files)
+			}
+			if marker := card.Blocks[idx].Data[0][0]; marker != byte(pos+1) {
+				t.Errorf("Expected block %d to hold chain position %d, but got: %d", idx, pos+1, marker)
+			}
+		}
+		if number := card.Blocks[chain[0]].TitleFrame.BlockNumber; number != byte(chain[0]+1) {
+			t.Errorf("Expected title frame block number %d, but got: %d", chain[0]+1, number)
+		}
+	}
+
+	for i := 6; i < NumBlocks; i++ {
+		if state := card.DirectoryFrames[i].BlockAllocationState; state != BlockAllocationStateFreeFresh {
+			t.Errorf("Expected block %d to be free, but got state: 0x%02X", i, uint32(state))
+		}
+	}
+
+	if report := card.Validate(); !report.Clean() {
+		t.Errorf("Expected compacted card to validate, but got: %v", report.Issues)
+	}
+}
@@ -12,22 +12,14 @@ func NewFormattedMemoryCard() *MemoryCard {
 	card := &MemoryCard{}
 
 	// Initialize header frame
-	card.Header.MagicBytes[0] = 'M'
-	card.Header.MagicBytes[1] = 'C'
+	card.Header.MagicBytes = HeaderFrameMagic
 	// Unused bytes are already zero-initialized
 	// Calculate header checksum: XOR of all bytes except checksum byte
 	card.Header.Checksum = calculateHeaderChecksum(&card.Header)
 
 	// Initialize all directory frames as free/freshly formatted
 	for i := 0; i < NumBlocks; i++ {
-		card.DirectoryFrames[i].BlockAllocationState = BlockAllocationStateFreeFresh
-		card.DirectoryFrames[i].FileSize = 0
-		card.DirectoryFrames[i].NextBlock = 0xFFFF
-		card.DirectoryFrames[i].FileName = NewEmptyFileName()
-		card.DirectoryFrames[i].Zero = 0
-		// Reserved bytes are already zero-initialized
-		// Calculate directory frame checksum
-		card.DirectoryFrames[i].Checksum = calculateDirectoryFrameChecksum(&card.DirectoryFrames[i])
+		card.DirectoryFrames[i] = newFreeDirectoryFrame()
 	}
 
 	// Initialize broken selectors (all set to 0xFFFFFFFF = no broken sectors)
@@ -52,16 +44,24 @@ func NewFormattedMemoryCard() *MemoryCard {
 		}
 	}
 
-	// Initialize write test frame (same as block 0 header)
-	card.WriteTestFrame[0] = 'M'
-	card.WriteTestFrame[1] = 'C'
-	for i := 2; i < len(card.WriteTestFrame)-1; i++ {
-		card.WriteTestFrame[i] = 0
-	}
-	// Calculate checksum for write test frame
-	card.WriteTestFrame[127] = calculateFrameChecksum(card.WriteTestFrame[:127])
+	// Initialize write test frame (same as the header frame)
+	card.WriteTestFrame = headerFrameBytes(&card.Header)
 
 	// Blocks are already zero-initialized (empty)
 
 	return card
 }
+
+// newFreeDirectoryFrame creates a directory frame in the free/freshly formatted state (0xA0)
+// with its checksum set.
+func newFreeDirectoryFrame() DirectoryFrame {
+	var frame DirectoryFrame
+	frame.BlockAllocationState = BlockAllocationStateFreeFresh
+	frame.FileSize = 0
+	frame.NextBlock = NoNextBlock
+	frame.FileName = NewEmptyFileName()
+	frame.Zero = 0
+	// Reserved bytes are already zero-initialized
+	frame.Checksum = calculateDirectoryFrameChecksum(&frame)
+	return frame
+}
@@ -1,5 +1,14 @@
 package memcard
 
+import (
+	"bytes"
+	"errors"
+)
+
+var (
+	ErrInvalidFileName = errors.New("invalid file name")
+)
+
 type MemoryCardID string
 
 const (
@@ -27,6 +36,25 @@ const (
 	BlockAllocationStateFreeDeletedLast     BlockAllocationState = 0xA3 // deleted (last block of file)
 )
 
+// IsFree reports whether the block can be allocated, that is whether it is
+// freshly formatted or belongs to a deleted file.
+func (s BlockAllocationState) IsFree() bool {
+	return s == BlockAllocationStateFreeFresh ||
+		s == BlockAllocationStateFreeDeletedFirst ||
+		s == BlockAllocationStateFreeDeletedMiddle ||
+		s == BlockAllocationStateFreeDeletedLast
+}
+
+// IsInUse reports whether the block belongs to a file stored on the card.
+func (s BlockAllocationState) IsInUse() bool {
+	return s == BlockAllocationStateInUseFirstOnlyBlock ||
+		s == BlockAllocationStateInUseMiddleBlock ||
+		s == BlockAllocationStateInUseLastBlock
+}
+
+// NoNextBlock is the NextBlock value of the last-or-only block of a file.
+const NoNextBlock uint16 = 0xFFFF
+
 const (
 	MemoryCardTotalSize = 131072 // 128 KB
 	BlockSize           = 8192   // 8 KB
@@ -42,16 +70,43 @@ type MemoryCard struct {
 	UnusedFrames               [7][128]byte
 	WriteTestFrame             [128]byte
 	Blocks                     [15]Block
+
+	meta  *cardMetadata
+	games *GameDatabase // database GetBlock looks games up in, the default database if nil
 }
 
+// HeaderFrameMagic is the "MC" ID at the start of the header frame.
+var HeaderFrameMagic = [2]byte{'M', 'C'}
+
 type HeaderFrame struct {
 	MagicBytes [2]byte
 	Unused     [125]byte
 	Checksum   byte
 }
 
+// headerFrameBytes returns the raw bytes of the header frame.
+func headerFrameBytes(header *HeaderFrame) [FrameSize]byte {
+	var frame [FrameSize]byte
+	copy(frame[0:2], header.MagicBytes[:])
+	copy(frame[2:127], header.Unused[:])
+	frame[127] = header.Checksum
+	return frame
+}
+
 type FileName [21]byte
 
+// NewFileName creates a file name from a string of 1 to 20 printable ASCII characters.
+// Use ParseFileName to split the name into its parts.
+func NewFileName(name string) (FileName, error) {
+	var fn FileName
+	if err := validateFileName(name); err != nil {
+		return fn, err
+	}
+
+	copy(fn[:], name)
+	return fn, nil
+}
+
 func NewEmptyFileName() FileName {
 	// TODO: Check what are the correct bytes for an empty filename
 	var fn FileName
@@ -61,28 +116,12 @@ func NewEmptyFileName() FileName {
 	return fn
 }
 
-func (f *FileName) Region() string {
-	regionCode := string(f[0:2])
-
-	switch regionCode {
-	case "BI":
-		return "Japan"
-	case "BE":
-		return "Europe"
-	case "BA":
-		return "America"
+// String returns the file name up to its terminating null byte.
+func (f *FileName) String() string {
+	if end := bytes.IndexByte(f[:], 0); end != -1 {
+		return string(f[:end])
 	}
-	return "Unknown"
-}
-
-func (f *FileName) GameCode() string {
-	gameCode := string(f[2:12])
-	return gameCode
-}
-
-func (f *FileName) GameName() string {
-	gameName := string(f[12:])
-	return gameName
+	return string(f[:])
 }
 
 type DirectoryFrame struct {
@@ -125,6 +164,9 @@ func (b *Block) CleanBlock() {
 	}
 }
 
+// TitleFrameMagic is the "SC" ID at the start of every title frame.
+var TitleFrameMagic = [2]byte{'S', 'C'}
+
 type BlockTitleFrame struct {
 	ID               [2]byte
 	IconDisplayFlag  IconDisplayFlag
@@ -134,4 +176,17 @@ type BlockTitleFrame struct {
 	IconColorPalette [16]uint16
 }
 
+// HasMagic reports whether the title frame starts with the "SC" ID and a known icon display flag.
+func (tf *BlockTitleFrame) HasMagic() bool {
+	if tf.ID != TitleFrameMagic {
+		return false
+	}
+
+	switch tf.IconDisplayFlag {
+	case IconDisplayFlagOneFrameIcon, IconDisplayFlagTwoFrameIcon, IconDisplayFlagThreeFrameIcon:
+		return true
+	}
+	return false
+}
+
 type DataFrame [128]byte
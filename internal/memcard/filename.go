@@ -0,0 +1,287 @@
+package memcard
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// File names of saves are up to 20 ASCII characters. Games name their saves after the product code:
+//
+//	"B" + region + product code + save identifier, e.g. "BASLUS-00892FF7SAVE"
+//
+// The region is 'I' (Japan), 'A' (America) or 'E' (Europe), the product code has the form
+// "SLUS-00892" and the save identifier is free-form text of up to 8 characters.
+// PocketStation executables use 'P' instead of the dash in the product code, e.g. "BISCPSP00123".
+// Homebrew and some tools use other names, they are kept as is.
+
+var (
+	ErrFileNameLength       = errors.New("file name must have 1 to 20 characters")
+	ErrFileNameCharacter    = errors.New("file name must only contain printable ASCII characters")
+	ErrFileNameRegion       = errors.New("file name does not start with a region")
+	ErrInvalidProductCode   = errors.New("invalid product code")
+	ErrSaveIdentifierLength = errors.New("save identifier must have at most 8 characters")
+)
+
+const (
+	maxFileNameLength       = len(FileName{}) - 1
+	productCodeOffset       = 2
+	productCodeLength       = 10
+	saveIdentifierOffset    = productCodeOffset + productCodeLength
+	maxSaveIdentifierLength = maxFileNameLength - saveIdentifierOffset
+	serialPrefixLength      = 4
+	serialNumberDigits      = 5
+)
+
+// Region is the region a save belongs to.
+type Region string
+
+const (
+	RegionUnknown Region = "Unknown"
+	RegionJapan   Region = "Japan"
+	RegionAmerica Region = "America"
+	RegionEurope  Region = "Europe"
+	RegionAsia    Region = "Asia"
+	RegionKorea   Region = "Korea"
+)
+
+var regionCodes = map[byte]Region{
+	'I': RegionJapan,
+	'A': RegionAmerica,
+	'E': RegionEurope,
+}
+
+// regionCode returns the region character of the file name. Asian and Korean releases run on
+// Japanese consoles and use the Japanese region.
+func regionCode(region Region) (byte, bool) {
+	switch region {
+	case RegionJapan, RegionAsia, RegionKorea:
+		return 'I', true
+	case RegionAmerica:
+		return 'A', true
+	case RegionEurope:
+		return 'E', true
+	}
+	return 0, false
+}
+
+// serialPrefixRegions maps serial prefixes to the region of their releases.
+var serialPrefixRegions = map[string]Region{
+	"SCUS": RegionAmerica, "SLUS": RegionAmerica, "PAPX": RegionJapan,
+	"SCES": RegionEurope, "SLES": RegionEurope, "SCED": RegionEurope, "SLED": RegionEurope,
+	"SCPS": RegionJapan, "SLPS": RegionJapan, "SLPM": RegionJapan, "SCPM": RegionJapan, "SIPS": RegionJapan,
+	"PCPX": RegionJapan, "PBPX": RegionJapan, "ESPM": RegionJapan, "SCZS": RegionJapan,
+	"SCAJ": RegionAsia, "SLAJ": RegionAsia,
+	"SCKA": RegionKorea, "SLKA": RegionKorea,
+}
+
+// FileNameKind tells how a file name is built.
+type FileNameKind int
+
+const (
+	FileNameKindOther         FileNameKind = iota // homebrew and other names without a product code
+	FileNameKindProduct                           // region, product code and save identifier
+	FileNameKindPocketStation                     // region, PocketStation product code and save identifier
+)
+
+// ParsedFileName is a file name split into its parts. Only Name is set for FileNameKindOther.
+type ParsedFileName struct {
+	Name           string // the whole file name
+	Kind           FileNameKind
+	Region         Region
+	SerialPrefix   string // e.g. "SLUS"
+	SerialNumber   int    // e.g. 892
+	SaveIdentifier string // free-form text after the product code
+}
+
+// ParseFileName parses a file name. Names that are no valid product file name are returned
+// as FileNameKindOther, only names that can not be stored on a card are rejected.
+func ParseFileName(name string) (ParsedFileName, error) {
+	if err := validateFileName(name); err != nil {
+		return ParsedFileName{}, err
+	}
+
+	parsed := ParsedFileName{Name: name, Kind: FileNameKindOther, Region: RegionUnknown}
+	if len(name) < saveIdentifierOffset || name[0] != 'B' {
+		return parsed, nil
+	}
+
+	region, knownRegion := regionCodes[name[1]]
+	prefix, number, pocketStation, err := parseProductCode(name[productCodeOffset:saveIdentifierOffset])
+	if !knownRegion || err != nil {
+		return parsed, nil
+	}
+
+	parsed.Kind = FileNameKindProduct
+	if pocketStation {
+		parsed.Kind = FileNameKindPocketStation
+	}
+	parsed.Region = region
+	parsed.SerialPrefix = prefix
+	parsed.SerialNumber = number
+	parsed.SaveIdentifier = name[saveIdentifierOffset:]
+	return parsed, nil
+}
+
+// NewProductFileName builds the file name of a save from its region, product code and save identifier.
+// For PocketStation executables the dash of the product code is replaced by 'P'.
+func NewProductFileName(region Region, serialPrefix string, serialNumber int, saveIdentifier string, pocketStation bool) (FileName, error) {
+	parsed := ParsedFileName{
+		Kind:           FileNameKindProduct,
+		Region:         region,
+		SerialPrefix:   serialPrefix,
+		SerialNumber:   serialNumber,
+		SaveIdentifier: saveIdentifier,
+	}
+	if pocketStation {
+		parsed.Kind = FileNameKindPocketStation
+	}
+	return parsed.FileName()
+}
+
+// ProductCode returns the product code in its canonical form, e.g. "SLUS-00892",
+// or "" for names without a product code.
+func (p ParsedFileName) ProductCode() string {
+	if p.Kind == FileNameKindOther {
+		return ""
+	}
+	return fmt.Sprintf("%s-%0*d", p.SerialPrefix, serialNumberDigits, p.SerialNumber)
+}
+
+// PrefixRegion returns the region of the releases with the serial prefix of the product code.
+// Unlike Region it tells Asian and Korean releases apart from Japanese ones.
+func (p ParsedFileName) PrefixRegion() Region {
+	if region, found := serialPrefixRegions[p.SerialPrefix]; found {
+		return region
+	}
+	return p.Region
+}
+
+// String returns the file name as stored on the card.
+func (p ParsedFileName) String() string {
+	if p.Kind == FileNameKindOther {
+		return p.Name
+	}
+
+	code, _ := regionCode(p.Region)
+	separator := "-"
+	if p.Kind == FileNameKindPocketStation {
+		separator = "P"
+	}
+	return fmt.Sprintf("B%c%s%s%0*d%s", code, p.SerialPrefix, separator, serialNumberDigits, p.SerialNumber, p.SaveIdentifier)
+}
+
+// Validate checks that the parts of the file name can be stored on a card.
+func (p ParsedFileName) Validate() error {
+	if p.Kind == FileNameKindOther {
+		return validateFileName(p.Name)
+	}
+
+	if _, found := regionCode(p.Region); !found {
+		return fmt.Errorf("%w: %w: %q", ErrInvalidFileName, ErrFileNameRegion, p.Region)
+	}
+	if len(p.SerialPrefix) != serialPrefixLength || !isUpperLetters(p.SerialPrefix) {
+		return fmt.Errorf("%w: %w: serial prefix %q must be 4 upper case letters", ErrInvalidFileName, ErrInvalidProductCode, p.SerialPrefix)
+	}
+	if p.SerialNumber < 0 || p.SerialNumber > 99999 {
+		return fmt.Errorf("%w: %w: serial number %d must have at most 5 digits", ErrInvalidFileName, ErrInvalidProductCode, p.SerialNumber)
+	}
+	if len(p.SaveIdentifier) > maxSaveIdentifierLength {
+		return fmt.Errorf("%w: %w: %q", ErrInvalidFileName, ErrSaveIdentifierLength, p.SaveIdentifier)
+	}
+	return validateFileName(p.String())
+}
+
+// FileName encodes the parsed file name back into the 21 byte file name field, padded with null bytes.
+func (p ParsedFileName) FileName() (FileName, error) {
+	if err := p.Validate(); err != nil {
+		return FileName{}, err
+	}
+
+	var fn FileName
+	copy(fn[:], p.String())
+	return fn, nil
+}
+
+// Parse parses the file name, see ParseFileName.
+func (f *FileName) Parse() (ParsedFileName, error) {
+	return ParseFileName(f.String())
+}
+
+// Region returns the region of the save, "Unknown" for names without a product code.
+func (f *FileName) Region() string {
+	parsed, _ := f.Parse()
+	if parsed.Region == "" {
+		return string(RegionUnknown)
+	}
+	return string(parsed.Region)
+}
+
+// GameCode returns the product code in its canonical form, e.g. "SLUS-00892",
+// or "" for names without a product code.
+func (f *FileName) GameCode() string {
+	parsed, _ := f.Parse()
+	return parsed.ProductCode()
+}
+
+// GameName returns the save identifier that follows the product code.
+func (f *FileName) GameName() string {
+	parsed, _ := f.Parse()
+	return parsed.SaveIdentifier
+}
+
+// parseProductCode parses a 10 character product code like "SLUS-00892" or the
+// PocketStation form "SCPSP10001".
+func parseProductCode(code string) (prefix string, number int, pocketStation bool, err error) {
+	if len(code) != productCodeLength {
+		return "", 0, false, ErrInvalidProductCode
+	}
+
+	prefix = code[:serialPrefixLength]
+	if !isUpperLetters(prefix) {
+		return "", 0, false, ErrInvalidProductCode
+	}
+
+	switch code[serialPrefixLength] {
+	case '-':
+	case 'P':
+		pocketStation = true
+	default:
+		return "", 0, false, ErrInvalidProductCode
+	}
+
+	digits := code[serialPrefixLength+1:]
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return "", 0, false, ErrInvalidProductCode
+		}
+	}
+
+	number, err = strconv.Atoi(digits)
+	if err != nil {
+		return "", 0, false, ErrInvalidProductCode
+	}
+	return prefix, number, pocketStation, nil
+}
+
+// validateFileName checks that the name fits into the file name field of a directory frame.
+func validateFileName(name string) error {
+	if name == "" || len(name) > maxFileNameLength {
+		return fmt.Errorf("%w: %w: %q", ErrInvalidFileName, ErrFileNameLength, name)
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] < 0x20 || name[i] > 0x7E {
+			return fmt.Errorf("%w: %w: %q", ErrInvalidFileName, ErrFileNameCharacter, name)
+		}
+	}
+	return nil
+}
+
+func isUpperLetters(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package memcard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        FileNameKind
+		region      Region
+		productCode string
+		saveID      string
+	}{
+		{name: "BASLUS-00892FF7SAVE", kind: FileNameKindProduct, region: RegionAmerica, productCode: "SLUS-00892", saveID: "FF7SAVE"},
+		{name: "BESCES-01234", kind: FileNameKindProduct, region: RegionEurope, productCode: "SCES-01234"},
+		{name: "BISLPS-0123401234567", kind: FileNameKindProduct, region: RegionJapan, productCode: "SLPS-01234", saveID: "01234567"},
+		{name: "BISCPSP10001POCKET", kind: FileNameKindPocketStation, region: RegionJapan, productCode: "SCPS-10001", saveID: "POCKET"},
+		{name: "HOMEBREW", kind: FileNameKindOther, region: RegionUnknown},
+		{name: "BXSLUS-00892", kind: FileNameKindOther, region: RegionUnknown},
+		{name: "BASLUS-0089X", kind: FileNameKindOther, region: RegionUnknown},
+		{name: "BAslus-00892", kind: FileNameKindOther, region: RegionUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseFileName(tt.name)
+			if err != nil {
+				t.Fatalf("Error parsing file name: %v", err)
+			}
+			if parsed.Kind != tt.kind || parsed.Region != tt.region || parsed.ProductCode() != tt.productCode || parsed.SaveIdentifier != tt.saveID {
+				t.Errorf("Expected kind %d, region %s, product code %q and save identifier %q, but got: %+v (%q)",
+					tt.kind, tt.region, tt.productCode, tt.saveID, parsed, parsed.ProductCode())
+			}
+			if parsed.String() != tt.name {
+				t.Errorf("Expected %q, but got: %q", tt.name, parsed.String())
+			}
+		})
+	}
+}
+
+func TestParseFileName_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "", err: ErrFileNameLength},
+		{name: "BASLUS-00892FF7SAVE01", err: ErrFileNameLength},
+		{name: "BASLUS-00892\x01", err: ErrFileNameCharacter},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseFileName(tt.name); !errors.Is(err, ErrInvalidFileName) || !errors.Is(err, tt.err) {
+			t.Errorf("Expected %v for %q, but got: %v", tt.err, tt.name, err)
+		}
+	}
+}
+
+func TestNewProductFileName(t *testing.T) {
+	fn, err := NewProductFileName(RegionAsia, "SCAJ", 20001, "DATA", false)
+	if err != nil {
+		t.Fatalf("Error creating file name: %v", err)
+	}
+	if fn.String() != "BISCAJ-20001DATA" {
+		t.Errorf("Expected BISCAJ-20001DATA, but got: %q", fn.String())
+	}
+	if fn[len(fn)-1] != 0 {
+		t.Errorf("Expected file name to be null padded, but got: %v", fn)
+	}
+
+	parsed, err := fn.Parse()
+	if err != nil {
+		t.Fatalf("Error parsing file name: %v", err)
+	}
+	if parsed.PrefixRegion() != RegionAsia || fn.Region() != "Japan" || fn.GameCode() != "SCAJ-20001" || fn.GameName() != "DATA" {
+		t.Errorf("Expected Asian release with a Japanese region, but got: %+v", parsed)
+	}
+
+	tests := []struct {
+		region Region
+		prefix string
+		number int
+		saveID string
+		err    error
+	}{
+		{region: RegionUnknown, prefix: "SLUS", err: ErrFileNameRegion},
+		{region: RegionAmerica, prefix: "slus", err: ErrInvalidProductCode},
+		{region: RegionAmerica, prefix: "SLU", err: ErrInvalidProductCode},
+		{region: RegionAmerica, prefix: "SLUS", number: 100000, err: ErrInvalidProductCode},
+		{region: RegionAmerica, prefix: "SLUS", saveID: "TOOLONGID", err: ErrSaveIdentifierLength},
+		{region: RegionAmerica, prefix: "SLUS", saveID: "SAVE\n", err: ErrFileNameCharacter},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewProductFileName(tt.region, tt.prefix, tt.number, tt.saveID, false); !errors.Is(err, tt.err) {
+			t.Errorf("Expected %v for %+v, but got: %v", tt.err, tt, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package memcard
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	ErrUnknownCardFormat  = errors.New("unknown memory card format")
+	ErrCommentTooLong     = errors.New("comment is too long")
+	ErrMissingImageHeader = errors.New("memory card image header is missing")
+)
+
+// CardFormat identifies the file format a memory card image is stored in.
+type CardFormat string
+
+const (
+	CardFormatRaw      CardFormat = "raw"      // plain 128 KB image (.mcr, .mcd, .srm, ...)
+	CardFormatDexDrive CardFormat = "gme"      // DexDrive image with a 3904 byte header (.gme)
+	CardFormatVGS      CardFormat = "vgs"      // Connectix Virtual Game Station image with a 64 byte header (.mem, .vgs)
+	CardFormatVMP      CardFormat = "vmp"      // PSP and PS Vita virtual memory card with a signed 128 byte header (.VMP)
+	CardFormatPrefixed CardFormat = "prefixed" // raw image behind a small header of unknown layout
+)
+
+// cardMetadata holds information about a memory card that is not part of the card itself,
+// but of the file format it was loaded from.
+type cardMetadata struct {
+	codec    CardCodec // codec the card was loaded with, nil for cards created in memory
+	comments [NumBlocks]string
+	header   []byte // header in front of the memory card, kept for header prefixed formats
+}
+
+// metadata returns the metadata of the card, creating it on first use.
+func (mc *MemoryCard) metadata() *cardMetadata {
+	if mc.meta == nil {
+		mc.meta = &cardMetadata{}
+	}
+	return mc.meta
+}
+
+// Clone returns a copy of the card that shares no memory with it, including the format and the comments
+// it is written back with. Changes can be made to a clone and kept only once they were written.
+func (mc *MemoryCard) Clone() *MemoryCard {
+	clone := *mc
+	if mc.meta != nil {
+		meta := *mc.meta
+		meta.header = slices.Clone(mc.meta.header)
+		clone.meta = &meta
+	}
+	return &clone
+}
+
+// Codec returns the codec the card was loaded with and is written back in,
+// or nil if the card was not loaded from a file.
+func (mc *MemoryCard) Codec() CardCodec {
+	if mc.meta == nil {
+		return nil
+	}
+	return mc.meta.codec
+}
+
+// Format returns the file format the card was loaded from, and is written back in.
+func (mc *MemoryCard) Format() CardFormat {
+	if codec := mc.Codec(); codec != nil {
+		return codec.Format()
+	}
+	return CardFormatRaw
+}
+
+// SetFormat changes the file format the card is written in to one of the formats of the default codec registry.
+func (mc *MemoryCard) SetFormat(format CardFormat) error {
+	codec, err := DefaultCodecRegistry().CardCodec(format)
+	if err != nil {
+		return err
+	}
+	return mc.SetCodec(codec)
+}
+
+// SetCodec changes the codec the card is written with.
+func (mc *MemoryCard) SetCodec(codec CardCodec) error {
+	if codec == nil {
+		return ErrUnknownCardFormat
+	}
+
+	if codec.Format() != mc.Format() {
+		// The layout of an unknown header can not be made up
+		if codec.Format() == CardFormatPrefixed {
+			return ErrMissingImageHeader
+		}
+
+		// A header kept from another format does not belong in the new format
+		if mc.meta != nil {
+			mc.meta.header = nil
+		}
+	}
+
+	mc.metadata().codec = codec
+	return nil
+}
+
+// Comment returns the comment stored for a block, as kept by DexDrive images.
+func (mc *MemoryCard) Comment(blockIndex int) string {
+	if mc.meta == nil || blockIndex < 0 || blockIndex >= NumBlocks {
+		return ""
+	}
+	return mc.meta.comments[blockIndex]
+}
+
+// SetComment changes the comment stored for a block.
+// Comments are only written to formats that support them.
+func (mc *MemoryCard) SetComment(blockIndex int, comment string) error {
+	if blockIndex < 0 || blockIndex >= NumBlocks {
+		return ErrInvalidBlockIndex
+	}
+
+	if len(comment) > dexDriveCommentSize {
+		return ErrCommentTooLong
+	}
+
+	if comment == "" && mc.meta == nil {
+		return nil
+	}
+
+	mc.metadata().comments[blockIndex] = comment
+	return nil
+}
+
+// copyComment copies the comment of a block on the source card to a block on this card.
+func (mc *MemoryCard) copyComment(blockIndex int, source *MemoryCard, sourceIndex int) {
+	if comment := source.Comment(sourceIndex); comment != "" || mc.meta != nil {
+		mc.metadata().comments[blockIndex] = comment
+	}
+}
@@ -0,0 +1,207 @@
+package memcard
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// The game database maps product codes to the English name, region and publisher of the game.
+// A CSV file is embedded in the binary, entries of the user file override it:
+//
+//	product_code,title,region,publisher
+//	SLUS-00892,Final Fantasy VIII,America,Square Electronic Arts
+//
+// Multi-disc games are listed with the product code of their first disc, which is the code their saves use.
+//
+// The embedded file is a starter list of popular games. Testers add the games of their cards to the user
+// file (see UserGameDatabasePath), rows that are useful to others are added to gamedb.csv in this package.
+
+//go:embed gamedb.csv
+var embeddedGameDatabase []byte
+
+const (
+	GameDatabaseFileName = "gamedb.csv"
+	userConfigDirName    = "PSXMemoryCardManager"
+	gameDatabaseColumns  = 4
+)
+
+var ErrInvalidGameDatabase = errors.New("invalid game database")
+
+// GameInfo describes a game of the game database.
+type GameInfo struct {
+	ProductCode string // canonical product code, e.g. "SLUS-00892"
+	Title       string // English name of the game
+	Region      Region
+	Publisher   string
+}
+
+// String returns the title with region and publisher, e.g. "Final Fantasy VIII (America, Square Electronic Arts)".
+func (g GameInfo) String() string {
+	details := []string{}
+	if g.Region != RegionUnknown && g.Region != "" {
+		details = append(details, string(g.Region))
+	}
+	if g.Publisher != "" {
+		details = append(details, g.Publisher)
+	}
+	if len(details) == 0 {
+		return g.Title
+	}
+	return fmt.Sprintf("%s (%s)", g.Title, strings.Join(details, ", "))
+}
+
+// GameDatabase looks up games by their product code.
+type GameDatabase struct {
+	games map[string]GameInfo
+	lock  sync.RWMutex
+}
+
+var defaultGameDatabase = NewGameDatabase()
+
+// DefaultGameDatabase returns the embedded database, used by cards without a database of their own.
+// It is shared by all cards, games of user files are loaded into a database from NewGameDatabase.
+func DefaultGameDatabase() *GameDatabase {
+	return defaultGameDatabase
+}
+
+// GameDatabase returns the database GetBlock looks games up in.
+func (mc *MemoryCard) GameDatabase() *GameDatabase {
+	if mc.games == nil {
+		return DefaultGameDatabase()
+	}
+	return mc.games
+}
+
+// SetGameDatabase sets the database GetBlock looks games up in, nil for the default database.
+func (mc *MemoryCard) SetGameDatabase(games *GameDatabase) {
+	mc.games = games
+}
+
+// NewGameDatabase creates a database with the games of the embedded database.
+func NewGameDatabase() *GameDatabase {
+	db := &GameDatabase{games: map[string]GameInfo{}}
+	if err := db.Load(bytes.NewReader(embeddedGameDatabase)); err != nil {
+		panic(fmt.Sprintf("embedded game database: %v", err))
+	}
+	return db
+}
+
+// Lookup returns the game with the product code, e.g. the GameCode of a file name.
+func (db *GameDatabase) Lookup(productCode string) (GameInfo, bool) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	game, found := db.games[productCode]
+	return game, found
+}
+
+// Len returns the number of games in the database.
+func (db *GameDatabase) Len() int {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return len(db.games)
+}
+
+// Load reads games from a CSV file with a header line. Games already in the database are replaced.
+// Nothing is added if the file is invalid.
+func (db *GameDatabase) Load(reader io.Reader) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1 // checked per line for a better error message
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGameDatabase, err)
+	}
+
+	games := make([]GameInfo, 0, len(records))
+	for i, record := range records {
+		if i == 0 {
+			continue // header
+		}
+
+		game, err := parseGameRecord(record)
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %w", ErrInvalidGameDatabase, i+1, err)
+		}
+		games = append(games, game)
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	for _, game := range games {
+		db.games[game.ProductCode] = game
+	}
+	return nil
+}
+
+// LoadFile reads games from a CSV file, see Load.
+func (db *GameDatabase) LoadFile(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	return db.Load(file)
+}
+
+// LoadUserFile reads the games of the user database, if the user created one.
+func (db *GameDatabase) LoadUserFile() error {
+	filePath, err := UserGameDatabasePath()
+	if err != nil {
+		return err
+	}
+
+	err = db.LoadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// UserGameDatabasePath returns the path of the user database in the user configuration directory.
+func UserGameDatabasePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, userConfigDirName, GameDatabaseFileName), nil
+}
+
+func parseGameRecord(record []string) (GameInfo, error) {
+	if len(record) != gameDatabaseColumns {
+		return GameInfo{}, fmt.Errorf("expected %d columns, but got %d", gameDatabaseColumns, len(record))
+	}
+
+	code := strings.TrimSpace(record[0])
+	_, _, _, err := parseProductCode(code)
+	if err != nil || code[serialPrefixLength] != '-' {
+		return GameInfo{}, fmt.Errorf("%w: %q", ErrInvalidProductCode, code)
+	}
+
+	region := Region(strings.TrimSpace(record[2]))
+	switch region {
+	case "":
+		region = RegionUnknown
+	case RegionUnknown, RegionJapan, RegionAmerica, RegionEurope, RegionAsia, RegionKorea:
+	default:
+		return GameInfo{}, fmt.Errorf("unknown region %q", region)
+	}
+
+	return GameInfo{
+		ProductCode: code,
+		Title:       strings.TrimSpace(record[1]),
+		Region:      region,
+		Publisher:   strings.TrimSpace(record[3]),
+	}, nil
+}
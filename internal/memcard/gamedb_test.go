@@ -0,0 +1,99 @@
+package memcard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGameDatabase_Embedded(t *testing.T) {
+	game, found := NewGameDatabase().Lookup("SLUS-00892")
+	if !found || game.Title != "Final Fantasy VIII" || game.Region != RegionAmerica {
+		t.Errorf("Expected Final Fantasy VIII, but got: %+v (found %t)", game, found)
+	}
+	if game.String() != "Final Fantasy VIII (America, Square Electronic Arts)" {
+		t.Errorf("Expected title with region and publisher, but got: %q", game.String())
+	}
+}
+
+func TestGameDatabase_LoadOverrides(t *testing.T) {
+	db := NewGameDatabase()
+	size := db.Len()
+
+	err := db.Load(strings.NewReader("product_code,title,region,publisher\n" +
+		"SLUS-00892,FF8 Test Build,,Tester\n" +
+		"SLPS-99999, Homebrew Test ,Japan,\n"))
+	if err != nil {
+		t.Fatalf("Error loading database: %v", err)
+	}
+
+	if db.Len() != size+1 {
+		t.Errorf("Expected %d games, but got: %d", size+1, db.Len())
+	}
+	if game, _ := db.Lookup("SLUS-00892"); game.Title != "FF8 Test Build" || game.Region != RegionUnknown || game.String() != "FF8 Test Build (Tester)" {
+		t.Errorf("Expected user entry to override embedded entry, but got: %+v", game)
+	}
+	if game, _ := db.Lookup("SLPS-99999"); game.String() != "Homebrew Test (Japan)" {
+		t.Errorf("Expected trimmed user entry, but got: %+v", game)
+	}
+}
+
+func TestGameDatabase_LoadInvalid(t *testing.T) {
+	tests := []string{
+		"product_code,title,region,publisher\nSLUS-00892,Title,America\n",
+		"product_code,title,region,publisher\nSLUS00892,Title,America,Publisher\n",
+		"product_code,title,region,publisher\nSCPSP10001,Title,Japan,Publisher\n",
+		"product_code,title,region,publisher\nSLUS-00892,Title,Mars,Publisher\n",
+	}
+
+	for _, data := range tests {
+		db := NewGameDatabase()
+		valid := strings.Replace(data, "publisher\n", "publisher\nSLPS-99999,Valid,Japan,Publisher\n", 1)
+		if err := db.Load(strings.NewReader(valid)); !errors.Is(err, ErrInvalidGameDatabase) {
+			t.Errorf("Expected ErrInvalidGameDatabase for %q, but got: %v", data, err)
+		}
+		if _, found := db.Lookup("SLPS-99999"); found {
+			t.Errorf("Expected no games to be added from invalid database %q", data)
+		}
+	}
+}
+
+func TestGetBlock_Game(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 0)
+	writeTestFile(t, card, "HOMEBREW", 1)
+
+	item, err := card.GetBlock(0)
+	if err != nil {
+		t.Fatalf("Error getting block: %v", err)
+	}
+	if item.ProductCode != "SLUS-00892" || item.Game == nil || item.Game.Title != "Final Fantasy VIII" {
+		t.Errorf("Expected block to describe Final Fantasy VIII, but got: %+v", item)
+	}
+
+	item, err = card.GetBlock(1)
+	if err != nil {
+		t.Fatalf("Error getting block: %v", err)
+	}
+	if item.ProductCode != "" || item.Game != nil {
+		t.Errorf("Expected homebrew block to have no game, but got: %+v", item)
+	}
+
+	// Games are looked up in the database of the card, the default database is not changed
+	games := NewGameDatabase()
+	if err := games.Load(strings.NewReader("product_code,title,region,publisher\nSLUS-00892,FF8 Test,America,\n")); err != nil {
+		t.Fatalf("Error loading database: %v", err)
+	}
+	card.SetGameDatabase(games)
+
+	item, err = card.GetBlock(0)
+	if err != nil {
+		t.Fatalf("Error getting block: %v", err)
+	}
+	if item.Game == nil || item.Game.Title != "FF8 Test" {
+		t.Errorf("Expected game of the card's database, but got: %+v", item.Game)
+	}
+	if game, _ := DefaultGameDatabase().Lookup("SLUS-00892"); game.Title != "Final Fantasy VIII" {
+		t.Errorf("Expected default database to be unchanged, but got: %+v", game)
+	}
+}
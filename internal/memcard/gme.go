@@ -0,0 +1,99 @@
+package memcard
+
+import (
+	"bytes"
+)
+
+// DexDrive images (.gme) start with a 3904 byte header followed by the raw memory card.
+// The header holds a copy of the allocation state and next block of each directory frame
+// and a 256 byte comment per block:
+//
+//	0x00-0x0A  "123-456-STD"
+//	0x12       0x01
+//	0x14       0x01
+//	0x15       'M'
+//	0x16-0x24  first byte of the allocation state of block 0-14
+//	0x26-0x34  first byte of the next block of block 0-14
+//	0x40-0xF3F comment of block 0-14, 256 bytes each
+const (
+	DexDriveHeaderSize    = 3904
+	dexDriveStatesOffset  = 0x16
+	dexDriveNextOffset    = 0x26
+	dexDriveCommentOffset = 0x40
+	dexDriveCommentSize   = 256
+)
+
+var dexDriveMagic = []byte("123-456-STD")
+
+// dexDriveCodec reads and writes DexDrive images.
+type dexDriveCodec struct{}
+
+func (dexDriveCodec) Format() CardFormat {
+	return CardFormatDexDrive
+}
+
+func (dexDriveCodec) Extensions() []string {
+	return []string{".gme"}
+}
+
+func (dexDriveCodec) Detect(data []byte) bool {
+	return isDexDriveImage(data)
+}
+
+func (dexDriveCodec) Decode(data []byte) (*MemoryCard, error) {
+	if !isDexDriveImage(data) {
+		return nil, ErrUnknownCardFormat
+	}
+	return readDexDriveImage(data)
+}
+
+func (dexDriveCodec) Encode(card *MemoryCard) ([]byte, error) {
+	return card.encodeDexDriveImage()
+}
+
+// isDexDriveImage reports whether the data is a DexDrive image.
+func isDexDriveImage(data []byte) bool {
+	return len(data) == DexDriveHeaderSize+MemoryCardTotalSize && bytes.HasPrefix(data, dexDriveMagic)
+}
+
+// readDexDriveImage decodes a DexDrive image and keeps its block comments.
+func readDexDriveImage(data []byte) (*MemoryCard, error) {
+	card, err := readRawImage(data[DexDriveHeaderSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	meta := card.metadata()
+	for i := range NumBlocks {
+		offset := dexDriveCommentOffset + i*dexDriveCommentSize
+		comment := data[offset : offset+dexDriveCommentSize]
+		if end := bytes.IndexByte(comment, 0); end != -1 {
+			comment = comment[:end]
+		}
+		meta.comments[i] = string(comment)
+	}
+
+	return card, nil
+}
+
+// encodeDexDriveImage encodes the card as DexDrive image, including its block comments.
+func (mc *MemoryCard) encodeDexDriveImage() ([]byte, error) {
+	raw, err := mc.encodeRawImage()
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, DexDriveHeaderSize)
+	copy(header, dexDriveMagic)
+	header[0x12] = 0x01
+	header[0x14] = 0x01
+	header[0x15] = 'M'
+
+	for i := range NumBlocks {
+		header[dexDriveStatesOffset+i] = byte(mc.DirectoryFrames[i].BlockAllocationState)
+		header[dexDriveNextOffset+i] = byte(mc.DirectoryFrames[i].NextBlock)
+		copy(header[dexDriveCommentOffset+i*dexDriveCommentSize:], mc.Comment(i))
+	}
+
+	return append(header, raw...), nil
+}
@@ -0,0 +1,52 @@
+package memcard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDexDriveImage_RoundTrip(t *testing.T) {
+	card, err := Open("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error opening card: %v", err)
+	}
+
+	if err := card.SetFormat(CardFormatDexDrive); err != nil {
+		t.Fatalf("Error setting format: %v", err)
+	}
+	if err := card.SetComment(1, "Silent Hill - before the boss"); err != nil {
+		t.Fatalf("Error setting comment: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "card.gme")
+	if err := card.Write(path); err != nil {
+		t.Fatalf("Error writing card: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading card: %v", err)
+	}
+	if len(data) != DexDriveHeaderSize+MemoryCardTotalSize {
+		t.Fatalf("Expected %d bytes, but got: %d", DexDriveHeaderSize+MemoryCardTotalSize, len(data))
+	}
+	if data[dexDriveStatesOffset+1] != byte(BlockAllocationStateInUseFirstOnlyBlock) {
+		t.Errorf("Expected header to hold the allocation state of block 1, but got: 0x%02X", data[dexDriveStatesOffset+1])
+	}
+
+	loaded, err := Open(path)
+	if err != nil {
+		t.Fatalf("Error opening written card: %v", err)
+	}
+
+	if loaded.Format() != CardFormatDexDrive {
+		t.Errorf("Expected format %s, but got: %s", CardFormatDexDrive, loaded.Format())
+	}
+	if comment := loaded.Comment(1); comment != "Silent Hill - before the boss" {
+		t.Errorf("Expected comment to be kept, but got: %q", comment)
+	}
+	if loaded.DirectoryFrames != card.DirectoryFrames || loaded.Blocks != card.Blocks {
+		t.Errorf("Expected card contents to be kept")
+	}
+}
@@ -0,0 +1,169 @@
+package memcard
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"time"
+)
+
+// The BIOS shows the frames of animated icons for a fixed number of vertical blanks:
+// 16 for icons with 2 frames, 11 for icons with 3 frames. The counts are documented for the PAL
+// BIOS with 50 vertical blanks per second, so frames are shown for 320 ms and 220 ms.
+const (
+	IconSize             = 16
+	twoFrameIconVSyncs   = 16
+	threeFrameIconVSyncs = 11
+	vsyncsPerSecond      = 50
+	contactSheetColumns  = 5
+	contactSheetRows     = NumBlocks / contactSheetColumns
+)
+
+var (
+	ErrInvalidIconScale = errors.New("invalid icon scale, expected 1 or more")
+)
+
+// IconExportFormat is the image format an icon is exported in.
+type IconExportFormat int
+
+const (
+	IconExportPNG         IconExportFormat = iota // first frame as PNG
+	IconExportGIF                                 // all frames as animated GIF
+	IconExportSpriteSheet                         // all frames side by side as PNG
+)
+
+// Extension returns the file extension of the format.
+func (f IconExportFormat) Extension() string {
+	if f == IconExportGIF {
+		return ".gif"
+	}
+	return ".png"
+}
+
+// IconFrameDelay returns how long the BIOS shows each frame of an icon with frameCount frames.
+// Icons with a single frame are not animated and have no delay.
+func IconFrameDelay(frameCount int) time.Duration {
+	vsyncs := 0
+	switch frameCount {
+	case 2:
+		vsyncs = twoFrameIconVSyncs
+	case 3:
+		vsyncs = threeFrameIconVSyncs
+	}
+	return time.Duration(vsyncs) * time.Second / vsyncsPerSecond
+}
+
+// iconFrameCount returns the number of frames the icon display flag shows.
+// Titles with an unknown flag show all frames.
+func iconFrameCount(flag IconDisplayFlag) int {
+	switch flag {
+	case IconDisplayFlagOneFrameIcon:
+		return 1
+	case IconDisplayFlagTwoFrameIcon:
+		return 2
+	}
+	return len(Block{}.IconFrames)
+}
+
+// iconImages converts the icon frames the title frame shows to images.
+func iconImages(block *Block, mode STPMode) []*image.Paletted {
+	return blockIcon(block).Images(mode)
+}
+
+// IconImages returns the icon frames of the save the block belongs to, the way the BIOS draws them.
+func (mc *MemoryCard) IconImages(blockIndex int) ([]*image.Paletted, error) {
+	icon, err := mc.Icon(blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	return icon.Images(STPOpaque), nil
+}
+
+// ExportIcon writes the icon of the save the block belongs to in the format, scaled by an integer factor.
+func (mc *MemoryCard) ExportIcon(w io.Writer, blockIndex int, format IconExportFormat, scale int) error {
+	if scale < 1 {
+		return ErrInvalidIconScale
+	}
+
+	frames, err := mc.IconImages(blockIndex)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case IconExportPNG:
+		return png.Encode(w, scalePaletted(frames[0], scale))
+	case IconExportGIF:
+		return encodeIconGIF(w, frames, scale)
+	case IconExportSpriteSheet:
+		return png.Encode(w, spriteSheet(frames, scale))
+	}
+	return fmt.Errorf("unknown icon export format %d", format)
+}
+
+// ExportContactSheet writes the icons of all blocks as PNG, laid out in 5 columns and 3 rows
+// in physical block order. Linked blocks show the icon of their save, free blocks stay transparent.
+func (mc *MemoryCard) ExportContactSheet(w io.Writer, scale int) error {
+	if scale < 1 {
+		return ErrInvalidIconScale
+	}
+
+	size := IconSize * scale
+	sheet := image.NewNRGBA(image.Rect(0, 0, contactSheetColumns*size, contactSheetRows*size))
+	for _, slot := range mc.ListSlots() {
+		if !slot.InUse() || slot.FirstBlock == NoBlockIndex {
+			continue
+		}
+
+		block := mc.readBlock(slot.FirstBlock)
+		icon := scalePaletted(iconImages(&block, STPOpaque)[0], scale)
+		at := image.Pt(slot.Index%contactSheetColumns*size, slot.Index/contactSheetColumns*size)
+		draw.Draw(sheet, icon.Bounds().Add(at), icon, image.Point{}, draw.Over)
+	}
+	return png.Encode(w, sheet)
+}
+
+// encodeIconGIF writes the frames as looping GIF with the BIOS frame delays.
+func encodeIconGIF(w io.Writer, frames []*image.Paletted, scale int) error {
+	delay := int(IconFrameDelay(len(frames)) / (10 * time.Millisecond))
+	animation := &gif.GIF{}
+	for _, frame := range frames {
+		animation.Image = append(animation.Image, scalePaletted(frame, scale))
+		animation.Delay = append(animation.Delay, delay)
+		// Transparent pixels must not show the previous frame
+		animation.Disposal = append(animation.Disposal, gif.DisposalBackground)
+	}
+	return gif.EncodeAll(w, animation)
+}
+
+// spriteSheet places the frames side by side. All frames of an icon share one palette.
+func spriteSheet(frames []*image.Paletted, scale int) *image.Paletted {
+	size := IconSize * scale
+	sheet := image.NewPaletted(image.Rect(0, 0, len(frames)*size, size), frames[0].Palette)
+	for i, frame := range frames {
+		scaled := scalePaletted(frame, scale)
+		draw.Draw(sheet, scaled.Bounds().Add(image.Pt(i*size, 0)), scaled, image.Point{}, draw.Src)
+	}
+	return sheet
+}
+
+// scalePaletted scales the image by an integer factor with nearest neighbor sampling, keeping its palette.
+func scalePaletted(img *image.Paletted, scale int) *image.Paletted {
+	if scale == 1 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	scaled := image.NewPaletted(image.Rect(0, 0, bounds.Dx()*scale, bounds.Dy()*scale), append(color.Palette{}, img.Palette...))
+	for y := range scaled.Rect.Dy() {
+		for x := range scaled.Rect.Dx() {
+			scaled.SetColorIndex(x, y, img.ColorIndexAt(bounds.Min.X+x/scale, bounds.Min.Y+y/scale))
+		}
+	}
+	return scaled
+}
@@ -0,0 +1,123 @@
+package memcard
+
+import (
+	"bytes"
+	"errors"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+)
+
+// newIconTestCard returns a card with a 3 frame save in blocks 1 and 2. Pixel (0,0) of frame i uses color i+1.
+func newIconTestCard(t *testing.T) *MemoryCard {
+	t.Helper()
+
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 1, 2)
+	title := &card.Blocks[1].TitleFrame
+	title.IconDisplayFlag = IconDisplayFlagThreeFrameIcon
+	title.IconColorPalette = [16]uint16{0x0000, 0x001F, 0x03E0, 0x7C00}
+	for i := range card.Blocks[1].IconFrames {
+		card.Blocks[1].IconFrames[i][0] = byte(i + 1)
+	}
+	return card
+}
+
+func TestExportIcon(t *testing.T) {
+	card := newIconTestCard(t)
+
+	tests := []struct {
+		format        IconExportFormat
+		width, height int
+	}{
+		{format: IconExportPNG, width: 32, height: 32},
+		{format: IconExportSpriteSheet, width: 96, height: 32},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := card.ExportIcon(&buf, 2, tt.format, 2); err != nil {
+			t.Fatalf("Error exporting icon: %v", err)
+		}
+
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatalf("Error decoding PNG: %v", err)
+		}
+		if img.Bounds().Dx() != tt.width || img.Bounds().Dy() != tt.height {
+			t.Errorf("Expected %dx%d image for format %d, but got: %v", tt.width, tt.height, tt.format, img.Bounds())
+		}
+		// Frame 2 of the sprite sheet starts with color 3 (blue)
+		if tt.format == IconExportSpriteSheet {
+			if _, _, b, a := img.At(64, 1).RGBA(); b != 0xFFFF || a != 0xFFFF {
+				t.Errorf("Expected third frame to start with blue, but got: %v", img.At(64, 1))
+			}
+		}
+		if _, _, _, a := img.At(2, 0).RGBA(); a != 0 {
+			t.Errorf("Expected color 0x0000 to stay transparent, but got: %v", img.At(2, 0))
+		}
+	}
+}
+
+func TestExportIcon_GIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newIconTestCard(t).ExportIcon(&buf, 1, IconExportGIF, 1); err != nil {
+		t.Fatalf("Error exporting icon: %v", err)
+	}
+
+	animation, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("Error decoding GIF: %v", err)
+	}
+	if len(animation.Image) != 3 {
+		t.Fatalf("Expected 3 frames, but got: %d", len(animation.Image))
+	}
+	for _, delay := range animation.Delay {
+		if delay != 22 {
+			t.Errorf("Expected delay of 11 vertical blanks (22/100 s), but got: %d", delay)
+		}
+	}
+	if IconFrameDelay(2) != 320*time.Millisecond || IconFrameDelay(1) != 0 {
+		t.Errorf("Expected BIOS frame delays, but got: %v and %v", IconFrameDelay(2), IconFrameDelay(1))
+	}
+}
+
+func TestExportIcon_Errors(t *testing.T) {
+	card := newIconTestCard(t)
+
+	if err := card.ExportIcon(&bytes.Buffer{}, 1, IconExportPNG, 0); !errors.Is(err, ErrInvalidIconScale) {
+		t.Errorf("Expected ErrInvalidIconScale, but got: %v", err)
+	}
+	if err := card.ExportIcon(&bytes.Buffer{}, 5, IconExportPNG, 1); !errors.Is(err, ErrBlockNotInUse) {
+		t.Errorf("Expected ErrBlockNotInUse for free block, but got: %v", err)
+	}
+	if err := card.ExportIcon(&bytes.Buffer{}, NumBlocks, IconExportPNG, 1); !errors.Is(err, ErrInvalidBlockIndex) {
+		t.Errorf("Expected ErrInvalidBlockIndex, but got: %v", err)
+	}
+}
+
+func TestExportContactSheet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newIconTestCard(t).ExportContactSheet(&buf, 2); err != nil {
+		t.Fatalf("Error exporting contact sheet: %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Error decoding PNG: %v", err)
+	}
+	if img.Bounds().Dx() != 5*32 || img.Bounds().Dy() != 3*32 {
+		t.Fatalf("Expected 160x96 image, but got: %v", img.Bounds())
+	}
+
+	// Blocks 1 and 2 show the first frame of the save, which starts with red
+	for _, x := range []int{32, 64} {
+		if r, _, _, a := img.At(x, 0).RGBA(); r != 0xFFFF || a != 0xFFFF {
+			t.Errorf("Expected icon at x=%d, but got: %v", x, img.At(x, 0))
+		}
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("Expected free block to be transparent, but got: %v", img.At(0, 0))
+	}
+}
@@ -0,0 +1,329 @@
+package memcard
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"slices"
+)
+
+// Icons are imported from 16x16 images with up to 3 frames: a PNG with a single frame, a PNG sprite
+// sheet with the frames side by side or an animated GIF. Images scaled by an integer factor, like
+// exported icons, are scaled back down. All frames share one palette of 16 15-bit BGR colors, the
+// colors are reduced with median cut. Pixels with less than half alpha become the transparent color 0x0000.
+
+const (
+	iconPaletteSize   = 16
+	iconOpaqueBlack   = iconColorSTPBit // black that is not transparent
+	iconAlphaOpaque   = 0x80
+	maxIconFrameCount = 3
+)
+
+var (
+	ErrInvalidIconImage  = errors.New("invalid icon image, expected 16x16 pixels or an integer multiple")
+	ErrInvalidIconFrames = errors.New("invalid number of icon frames, expected 1 to 3")
+)
+
+// IconColorFromColor converts a color to a 15-bit BGR palette color. Colors with less than half
+// alpha become the transparent color 0x0000, black becomes 0x8000 so it stays opaque.
+func IconColorFromColor(c color.Color) uint16 {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if nrgba.A < iconAlphaOpaque {
+		return iconTransparent
+	}
+
+	bgr := uint16(nrgba.R>>3) | uint16(nrgba.G>>3)<<5 | uint16(nrgba.B>>3)<<10
+	if bgr == iconTransparent {
+		return iconOpaqueBlack
+	}
+	return bgr
+}
+
+// EncodeIcon quantizes up to 3 frames of 16x16 pixels to a shared 16 color palette.
+func EncodeIcon(frames []image.Image) ([iconPaletteSize]uint16, []IconBitmapFrame, error) {
+	var palette [iconPaletteSize]uint16
+	if len(frames) < 1 || len(frames) > maxIconFrameCount {
+		return palette, nil, ErrInvalidIconFrames
+	}
+
+	colors := make([][IconSize * IconSize]uint16, len(frames))
+	counts := map[uint16]int{}
+	for i, frame := range frames {
+		bounds := frame.Bounds()
+		if bounds.Dx() != IconSize || bounds.Dy() != IconSize {
+			return palette, nil, fmt.Errorf("%w: frame %d is %dx%d", ErrInvalidIconImage, i, bounds.Dx(), bounds.Dy())
+		}
+
+		for y := range IconSize {
+			for x := range IconSize {
+				c := IconColorFromColor(frame.At(bounds.Min.X+x, bounds.Min.Y+y))
+				colors[i][y*IconSize+x] = c
+				counts[c]++
+			}
+		}
+	}
+
+	// The transparent color keeps its own palette entry, the other colors share the rest
+	opaque := map[uint16]int{}
+	for c, n := range counts {
+		if c != iconTransparent {
+			opaque[c] = n
+		}
+	}
+	available := iconPaletteSize
+	if counts[iconTransparent] > 0 {
+		available--
+	}
+
+	entries := quantizeIconColors(opaque, available)
+	if counts[iconTransparent] > 0 {
+		entries = append([]uint16{iconTransparent}, entries...)
+	}
+	copy(palette[:], entries)
+
+	bitmaps := make([]IconBitmapFrame, len(frames))
+	indices := map[uint16]byte{}
+	for i := range colors {
+		for p, c := range colors[i] {
+			index, found := indices[c]
+			if !found {
+				index = nearestIconColor(entries, c)
+				indices[c] = index
+			}
+			bitmaps[i].SetPixelAt(p%IconSize, p/IconSize, index)
+		}
+	}
+	return palette, bitmaps, nil
+}
+
+// DecodeIconImages reads the frames of an icon from a PNG or GIF image, see EncodeIcon.
+func DecodeIconImages(r io.Reader) ([]image.Image, error) {
+	reader := bufio.NewReader(r)
+	magic, _ := reader.Peek(3)
+	if bytes.Equal(magic, []byte("GIF")) {
+		return decodeIconGIF(reader)
+	}
+
+	img, err := png.Decode(reader)
+	if err != nil {
+		return nil, err
+	}
+	return splitIconFrames(img)
+}
+
+// SetIcon replaces the icon of the save the block belongs to with the quantized frames, see EncodeIcon.
+func (mc *MemoryCard) SetIcon(blockIndex int, frames []image.Image) error {
+	palette, bitmaps, err := EncodeIcon(frames)
+	if err != nil {
+		return err
+	}
+	return mc.WriteIcon(blockIndex, Icon{Palette: palette, Frames: bitmaps})
+}
+
+// ImportIcon replaces the icon of the save the block belongs to with a PNG or GIF image.
+func (mc *MemoryCard) ImportIcon(blockIndex int, r io.Reader) error {
+	frames, err := DecodeIconImages(r)
+	if err != nil {
+		return err
+	}
+	return mc.SetIcon(blockIndex, frames)
+}
+
+// decodeIconGIF returns the frames of a GIF as they are shown, with the disposal of each frame applied.
+func decodeIconGIF(r io.Reader) ([]image.Image, error) {
+	animation, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(animation.Image) < 1 || len(animation.Image) > maxIconFrameCount {
+		return nil, ErrInvalidIconFrames
+	}
+
+	bounds := image.Rect(0, 0, animation.Config.Width, animation.Config.Height)
+	canvas := image.NewNRGBA(bounds)
+	frames := []image.Image{}
+	for i, frame := range animation.Image {
+		previous := slices.Clone(canvas.Pix)
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		img, err := scaleDownIcon(canvas)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, img)
+
+		switch animation.Disposal[i] {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous)
+		}
+	}
+	return frames, nil
+}
+
+// splitIconFrames splits a single icon or a sprite sheet into its frames.
+func splitIconFrames(img image.Image) ([]image.Image, error) {
+	bounds := img.Bounds()
+	size := bounds.Dy()
+	if size < IconSize || size%IconSize != 0 || bounds.Dx()%size != 0 {
+		return nil, fmt.Errorf("%w: image is %dx%d", ErrInvalidIconImage, bounds.Dx(), bounds.Dy())
+	}
+
+	count := bounds.Dx() / size
+	if count < 1 || count > maxIconFrameCount {
+		return nil, ErrInvalidIconFrames
+	}
+
+	frames := []image.Image{}
+	for i := range count {
+		frame := image.NewNRGBA(image.Rect(0, 0, size, size))
+		draw.Draw(frame, frame.Bounds(), img, bounds.Min.Add(image.Pt(i*size, 0)), draw.Src)
+
+		scaled, err := scaleDownIcon(frame)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, scaled)
+	}
+	return frames, nil
+}
+
+// scaleDownIcon scales a square image whose size is a multiple of 16 down to 16x16 pixels,
+// taking the top left pixel of each scaled pixel.
+func scaleDownIcon(img *image.NRGBA) (*image.NRGBA, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() != bounds.Dy() || bounds.Dx() < IconSize || bounds.Dx()%IconSize != 0 {
+		return nil, fmt.Errorf("%w: image is %dx%d", ErrInvalidIconImage, bounds.Dx(), bounds.Dy())
+	}
+
+	scale := bounds.Dx() / IconSize
+	icon := image.NewNRGBA(image.Rect(0, 0, IconSize, IconSize))
+	for y := range IconSize {
+		for x := range IconSize {
+			icon.SetNRGBA(x, y, img.NRGBAAt(bounds.Min.X+x*scale, bounds.Min.Y+y*scale))
+		}
+	}
+	return icon, nil
+}
+
+// quantizeIconColors reduces the colors to at most n colors with median cut, weighting each color
+// by the number of its pixels. Colors are returned in a stable order.
+func quantizeIconColors(counts map[uint16]int, n int) []uint16 {
+	colors := make([]uint16, 0, len(counts))
+	for c := range counts {
+		colors = append(colors, c)
+	}
+	slices.Sort(colors)
+
+	if len(colors) <= n {
+		return colors
+	}
+
+	boxes := [][]uint16{colors}
+	for len(boxes) < n {
+		// Split the box with the widest channel range
+		widest, channel, widestRange := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			for ch := range 3 {
+				if r := channelRange(box, ch); r > widestRange {
+					widest, channel, widestRange = i, ch, r
+				}
+			}
+		}
+		if widest == -1 {
+			break
+		}
+
+		box := boxes[widest]
+		slices.SortStableFunc(box, func(a, b uint16) int {
+			return int(colorChannel(a, channel)) - int(colorChannel(b, channel))
+		})
+
+		total := 0
+		for _, c := range box {
+			total += counts[c]
+		}
+		split, sum := 1, counts[box[0]]
+		for split < len(box)-1 && sum+counts[box[split]] <= total/2 {
+			sum += counts[box[split]]
+			split++
+		}
+
+		boxes = slices.Replace(boxes, widest, widest+1, box[:split], box[split:])
+	}
+
+	palette := make([]uint16, 0, len(boxes))
+	for _, box := range boxes {
+		palette = append(palette, averageIconColor(box, counts))
+	}
+	return palette
+}
+
+// nearestIconColor returns the index of the palette color closest to c.
+// The transparent color only matches itself.
+func nearestIconColor(palette []uint16, c uint16) byte {
+	best, bestDistance := 0, -1
+	for i, p := range palette {
+		if p == c {
+			return byte(i)
+		}
+		if p == iconTransparent || c == iconTransparent {
+			continue
+		}
+
+		distance := 0
+		for ch := range 3 {
+			d := int(colorChannel(p, ch)) - int(colorChannel(c, ch))
+			distance += d * d
+		}
+		if bestDistance == -1 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return byte(best)
+}
+
+func averageIconColor(box []uint16, counts map[uint16]int) uint16 {
+	var sums [3]int
+	total := 0
+	for _, c := range box {
+		for ch := range 3 {
+			sums[ch] += int(colorChannel(c, ch)) * counts[c]
+		}
+		total += counts[c]
+	}
+
+	var c uint16
+	for ch := range 3 {
+		c |= uint16((sums[ch]+total/2)/total) << (5 * ch)
+	}
+	if c == iconTransparent {
+		return iconOpaqueBlack
+	}
+	return c
+}
+
+func channelRange(box []uint16, channel int) int {
+	low, high := uint16(iconColorChannel), uint16(0)
+	for _, c := range box {
+		v := colorChannel(c, channel)
+		low, high = min(low, v), max(high, v)
+	}
+	return int(high) - int(low)
+}
+
+// colorChannel returns the red (0), green (1) or blue (2) channel of a 15-bit BGR color.
+func colorChannel(c uint16, channel int) uint16 {
+	return c >> (5 * channel) & iconColorChannel
+}
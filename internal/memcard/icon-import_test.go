@@ -0,0 +1,128 @@
+package memcard
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestIconColorFromColor(t *testing.T) {
+	tests := []struct {
+		color color.Color
+		want  uint16
+	}{
+		{color: color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}, want: 0x7FFF},
+		{color: color.NRGBA{R: 0xFF, A: 0xFF}, want: 0x001F},
+		{color: color.NRGBA{B: 0x84, A: 0xFF}, want: 0x4000},
+		{color: color.NRGBA{A: 0xFF}, want: 0x8000},
+		{color: color.NRGBA{R: 0xFF, A: 0x7F}, want: 0x0000},
+		{color: color.Transparent, want: 0x0000},
+	}
+
+	for _, tt := range tests {
+		if got := IconColorFromColor(tt.color); got != tt.want {
+			t.Errorf("Expected %#04x for %v, but got: %#04x", tt.want, tt.color, got)
+		}
+	}
+}
+
+func TestEncodeIcon_QuantizesColors(t *testing.T) {
+	// A gradient with 256 colors and a transparent row
+	img := image.NewNRGBA(image.Rect(0, 0, IconSize, IconSize))
+	for y := range IconSize {
+		for x := range IconSize {
+			if y == 0 {
+				continue
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 16), G: uint8(y * 16), B: 0x80, A: 0xFF})
+		}
+	}
+
+	palette, bitmaps, err := EncodeIcon([]image.Image{img})
+	if err != nil {
+		t.Fatalf("Error encoding icon: %v", err)
+	}
+	if len(bitmaps) != 1 || palette[0] != 0x0000 {
+		t.Fatalf("Expected 1 frame with transparent color 0, but got: %d frames, palette %04x", len(bitmaps), palette)
+	}
+	for i, c := range palette[1:] {
+		if c == 0x0000 {
+			t.Errorf("Expected 15 opaque colors, but color %d is transparent", i+1)
+		}
+	}
+
+	decoded := bitmaps[0].ToImage(palette)
+	for y := range IconSize {
+		for x := range IconSize {
+			_, _, _, a := decoded.At(x, y).RGBA()
+			if (y == 0) != (a == 0) {
+				t.Fatalf("Expected only the first row to be transparent, but pixel (%d,%d) has alpha %d", x, y, a)
+			}
+		}
+	}
+}
+
+func TestImportIcon_RoundTrip(t *testing.T) {
+	for _, format := range []IconExportFormat{IconExportSpriteSheet, IconExportGIF} {
+		source := newIconTestCard(t)
+		var buf bytes.Buffer
+		if err := source.ExportIcon(&buf, 1, format, 3); err != nil {
+			t.Fatalf("Error exporting icon: %v", err)
+		}
+
+		card := NewFormattedMemoryCard()
+		writeTestFile(t, card, "BASLUS-00892HOMEBREW", 4, 5)
+		if err := card.ImportIcon(5, &buf); err != nil {
+			t.Fatalf("Error importing icon in format %d: %v", format, err)
+		}
+
+		if flag := card.Blocks[4].TitleFrame.IconDisplayFlag; flag != IconDisplayFlagThreeFrameIcon {
+			t.Errorf("Expected icon display flag %#x, but got: %#x", IconDisplayFlagThreeFrameIcon, flag)
+		}
+
+		want, _ := source.IconImages(1)
+		got, _ := card.IconImages(4)
+		for i := range want {
+			for y := range IconSize {
+				for x := range IconSize {
+					if w, g := color.NRGBAModel.Convert(want[i].At(x, y)), color.NRGBAModel.Convert(got[i].At(x, y)); w != g {
+						t.Fatalf("Expected frame %d pixel (%d,%d) to be %v in format %d, but got: %v", i, x, y, w, format, g)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestImportIcon_Errors(t *testing.T) {
+	card := newIconTestCard(t)
+
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		err    error
+	}{
+		{name: "not square", width: 17, height: 16, err: ErrInvalidIconImage},
+		{name: "too small", width: 8, height: 8, err: ErrInvalidIconImage},
+		{name: "4 frames", width: 64, height: 16, err: ErrInvalidIconFrames},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, tt.width, tt.height))); err != nil {
+			t.Fatalf("Error encoding PNG: %v", err)
+		}
+		if err := card.ImportIcon(1, &buf); !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, but got: %v", tt.name, tt.err, err)
+		}
+	}
+
+	frame := image.NewNRGBA(image.Rect(0, 0, IconSize, IconSize))
+	if err := card.SetIcon(0, []image.Image{frame}); !errors.Is(err, ErrBlockNotInUse) {
+		t.Errorf("Expected ErrBlockNotInUse for free block, but got: %v", err)
+	}
+}
@@ -8,6 +8,120 @@ import (
 // Icon Image is 16x16 pixels, 4 bits per pixel (16 colors), so 128 bytes per frame
 type IconBitmapFrame [128]byte
 
+// Palette colors are 15-bit BGR with the semi-transparency (STP) bit on top:
+//
+//	bit 15     STP
+//	bit 10-14  blue
+//	bit 5-9    green
+//	bit 0-4    red
+//
+// Like in every PS1 texture, the color 0x0000 is fully transparent. 0x8000 is black with the STP bit set.
+const (
+	iconColorSTPBit      = 0x8000
+	iconColorChannel     = 0x1F
+	iconTransparent      = 0x0000
+	semiTransparentAlpha = 0x80
+)
+
+// STPMode tells how the STP bit of icon colors other than 0x0000 is interpreted.
+type STPMode int
+
+const (
+	// STPOpaque ignores the STP bit, the way the BIOS memory card screen draws icons.
+	STPOpaque STPMode = iota
+	// STPSemiTransparent draws colors with the STP bit half transparent, like the GPU
+	// does with semi-transparency mode 0 (B/2 + F/2).
+	STPSemiTransparent
+	// STPTransparent draws colors with the STP bit fully transparent.
+	STPTransparent
+)
+
+// IconColorToNRGBA converts a 15-bit BGR palette color to 8-bit channels. The 5-bit channels
+// are widened by bit replication, so 0x1F becomes 0xFF.
+func IconColorToNRGBA(c uint16, mode STPMode) color.NRGBA {
+	if c == iconTransparent {
+		return color.NRGBA{}
+	}
+
+	rgba := color.NRGBA{
+		R: expandChannel(c),
+		G: expandChannel(c >> 5),
+		B: expandChannel(c >> 10),
+		A: 0xFF,
+	}
+
+	if c&iconColorSTPBit != 0 {
+		switch mode {
+		case STPSemiTransparent:
+			rgba.A = semiTransparentAlpha
+		case STPTransparent:
+			rgba.A = 0
+		}
+	}
+	return rgba
+}
+
+func expandChannel(c uint16) uint8 {
+	v := uint8(c & iconColorChannel)
+	return v<<3 | v>>2
+}
+
+// Icon is the icon of a save: its palette and the 1 to 3 frames the icon display flag shows.
+type Icon struct {
+	Palette [16]uint16
+	Frames  []IconBitmapFrame
+}
+
+// Images converts the frames of the icon to images, see ToImageWithMode.
+func (i Icon) Images(mode STPMode) []*image.Paletted {
+	images := []*image.Paletted{}
+	for _, frame := range i.Frames {
+		images = append(images, frame.ToImageWithMode(i.Palette, mode))
+	}
+	return images
+}
+
+// Icon returns the icon of the save the block belongs to.
+func (mc *MemoryCard) Icon(blockIndex int) (Icon, error) {
+	first, err := mc.saveFirstBlock(blockIndex)
+	if err != nil {
+		return Icon{}, err
+	}
+
+	block := mc.readBlock(first)
+	return blockIcon(&block), nil
+}
+
+// WriteIcon replaces the icon of the save the block belongs to. The icon display flag is set
+// for the number of frames, unused icon frames are cleared.
+func (mc *MemoryCard) WriteIcon(blockIndex int, icon Icon) error {
+	if len(icon.Frames) < 1 || len(icon.Frames) > maxIconFrameCount {
+		return ErrInvalidIconFrames
+	}
+
+	first, err := mc.saveFirstBlock(blockIndex)
+	if err != nil {
+		return err
+	}
+
+	block := mc.readBlock(first)
+	block.TitleFrame.IconColorPalette = icon.Palette
+	block.TitleFrame.IconDisplayFlag = IconDisplayFlagOneFrameIcon + IconDisplayFlag(len(icon.Frames)-1)
+	block.IconFrames = [3]IconBitmapFrame{}
+	copy(block.IconFrames[:], icon.Frames)
+	mc.writeBlock(first, block)
+	return nil
+}
+
+// blockIcon returns the icon of the title frame of the block.
+func blockIcon(block *Block) Icon {
+	count := iconFrameCount(block.TitleFrame.IconDisplayFlag)
+	return Icon{
+		Palette: block.TitleFrame.IconColorPalette,
+		Frames:  append([]IconBitmapFrame{}, block.IconFrames[:count]...),
+	}
+}
+
 func (ib *IconBitmapFrame) PixelAt(x, y int) byte {
 	if x < 0 || x >= 16 || y < 0 || y >= 16 {
 		return 0
@@ -20,15 +134,32 @@ func (ib *IconBitmapFrame) PixelAt(x, y int) byte {
 	return (pixelData >> 4) & 0x0F // Upper nibble
 }
 
+// SetPixelAt sets the palette index of the pixel.
+func (ib *IconBitmapFrame) SetPixelAt(x, y int, index byte) {
+	if x < 0 || x >= 16 || y < 0 || y >= 16 {
+		return
+	}
+	byteIndex := y*8 + x/2
+	if x%2 == 0 {
+		ib[byteIndex] = ib[byteIndex]&0xF0 | index&0x0F // Lower nibble
+	} else {
+		ib[byteIndex] = ib[byteIndex]&0x0F | index<<4 // Upper nibble
+	}
+}
+
+// ToImage converts the icon to an image the way the BIOS draws it, see STPOpaque.
 func (ib *IconBitmapFrame) ToImage(IconColorPalette [16]uint16) image.Image {
+	return ib.ToImageWithMode(IconColorPalette, STPOpaque)
+}
+
+// ToImageWithMode converts the icon to a paletted image with non-premultiplied colors.
+// The color 0x0000 is transparent, mode tells how the STP bit of the other colors is interpreted.
+func (ib *IconBitmapFrame) ToImageWithMode(IconColorPalette [16]uint16, mode STPMode) *image.Paletted {
 
 	paletteColors := color.Palette{}
 
 	for _, c := range IconColorPalette {
-		r := uint8((c & 0x1F) << 3)
-		g := uint8(((c >> 5) & 0x1F) << 3)
-		b := uint8(((c >> 10) & 0x1F) << 3)
-		paletteColors = append(paletteColors, color.RGBA{R: r, G: g, B: b, A: 255})
+		paletteColors = append(paletteColors, IconColorToNRGBA(c, mode))
 	}
 
 	img := image.NewPaletted(image.Rect(0, 0, 16, 16), paletteColors)
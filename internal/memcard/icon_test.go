@@ -0,0 +1,81 @@
+package memcard
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+)
+
+func TestIconColorToNRGBA(t *testing.T) {
+	tests := []struct {
+		name  string
+		color uint16
+		mode  STPMode
+		want  color.NRGBA
+	}{
+		{name: "transparent", color: 0x0000, mode: STPOpaque, want: color.NRGBA{}},
+		{name: "transparent semi", color: 0x0000, mode: STPSemiTransparent, want: color.NRGBA{}},
+		{name: "white", color: 0x7FFF, mode: STPOpaque, want: color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}},
+		{name: "red", color: 0x001F, mode: STPOpaque, want: color.NRGBA{R: 0xFF, A: 0xFF}},
+		{name: "green", color: 0x03E0, mode: STPOpaque, want: color.NRGBA{G: 0xFF, A: 0xFF}},
+		{name: "blue", color: 0x7C00, mode: STPOpaque, want: color.NRGBA{B: 0xFF, A: 0xFF}},
+		{name: "replicated bits", color: 0x0010, mode: STPOpaque, want: color.NRGBA{R: 0x84, A: 0xFF}},
+		{name: "black with stp", color: 0x8000, mode: STPOpaque, want: color.NRGBA{A: 0xFF}},
+		{name: "stp opaque", color: 0xFFFF, mode: STPOpaque, want: color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}},
+		{name: "stp semi", color: 0xFFFF, mode: STPSemiTransparent, want: color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0x80}},
+		{name: "stp transparent", color: 0xFFFF, mode: STPTransparent, want: color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF}},
+		{name: "no stp transparent", color: 0x7FFF, mode: STPTransparent, want: color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IconColorToNRGBA(tt.color, tt.mode); got != tt.want {
+				t.Errorf("Expected %v, but got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIconBitmapFrame_ToImage(t *testing.T) {
+	var frame IconBitmapFrame
+	frame[0] = 0x10 // pixel (0,0) uses color 0, pixel (1,0) color 1
+	palette := [16]uint16{0x0000, 0x7FFF}
+
+	img := frame.ToImage(palette)
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("Expected color 0x0000 to be transparent, but got alpha: %d", a)
+	}
+	if r, g, b, a := img.At(1, 0).RGBA(); r != 0xFFFF || g != 0xFFFF || b != 0xFFFF || a != 0xFFFF {
+		t.Errorf("Expected opaque white, but got: %d %d %d %d", r, g, b, a)
+	}
+}
+
+func TestWriteIcon(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 3, 7)
+
+	var frame IconBitmapFrame
+	frame.SetPixelAt(1, 2, 5)
+	frame.SetPixelAt(2, 2, 7)
+	icon := Icon{Palette: [16]uint16{5: 0x7FFF, 7: 0x001F}, Frames: []IconBitmapFrame{frame, frame}}
+
+	// The icon is stored in the first block of the save
+	if err := card.WriteIcon(7, icon); err != nil {
+		t.Fatalf("Error writing icon: %v", err)
+	}
+	if flag := card.Blocks[3].TitleFrame.IconDisplayFlag; flag != IconDisplayFlagTwoFrameIcon {
+		t.Errorf("Expected icon display flag %#x, but got: %#x", IconDisplayFlagTwoFrameIcon, flag)
+	}
+
+	got, err := card.Icon(3)
+	if err != nil {
+		t.Fatalf("Error reading icon: %v", err)
+	}
+	if got.Palette != icon.Palette || len(got.Frames) != 2 || got.Frames[1].PixelAt(1, 2) != 5 || got.Frames[1].PixelAt(2, 2) != 7 {
+		t.Errorf("Expected written icon, but got: %+v", got)
+	}
+
+	if err := card.WriteIcon(3, Icon{}); !errors.Is(err, ErrInvalidIconFrames) {
+		t.Errorf("Expected ErrInvalidIconFrames, but got: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package memcard
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The frames of a memory card are encoded by hand at fixed offsets, which is much faster than
+// encoding/binary reflecting over the nested structs. All multi-byte values are little endian.
+//
+// Directory frame:
+//
+//	0x00-0x03  allocation state
+//	0x04-0x07  file size
+//	0x08-0x09  next block
+//	0x0A-0x1E  file name
+//	0x1F       zero
+//	0x20-0x7E  reserved
+//	0x7F       checksum
+//
+// Title frame:
+//
+//	0x00-0x01  "SC"
+//	0x02       icon display flag
+//	0x03       block number
+//	0x04-0x43  title (Shift-JIS)
+//	0x44-0x5F  reserved
+//	0x60-0x7F  icon color palette, 16 colors
+const (
+	directoryFrameNameOffset     = 0x0A
+	directoryFrameZeroOffset     = 0x1F
+	directoryFrameReservedOffset = 0x20
+	titleFrameTitleOffset        = 0x04
+	titleFrameReservedOffset     = 0x44
+	titleFramePaletteOffset      = 0x60
+	checksumOffset               = FrameSize - 1
+)
+
+// Offsets of the sections of a raw memory card image.
+const (
+	headerOffset         = HeaderFrameIndex * FrameSize
+	directoryOffset      = FirstDirectoryFrameIndex * FrameSize
+	brokenSelectorOffset = FirstBrokenSelectorFrameIndex * FrameSize
+	replacementOffset    = 36 * FrameSize
+	unusedFramesOffset   = 56 * FrameSize
+	writeTestFrameOffset = WriteTestFrameIndex * FrameSize
+	blocksOffset         = BlockSize
+	iconFramesOffset     = FrameSize
+	dataFramesOffset     = 4 * FrameSize
+)
+
+var ErrInvalidFrameSize = errors.New("invalid frame size, expected 128 bytes")
+
+// ImageFrame returns the frame with the card wide frame number (0-1023) of a raw memory card image.
+// The frame shares its memory with the image, changes to it change the image.
+func ImageFrame(image []byte, frameNumber int) ([]byte, error) {
+	if len(image) != MemoryCardTotalSize {
+		return nil, ErrInvalidMemoryCardSize
+	}
+	if frameNumber < 0 || frameNumber >= NumFrames {
+		return nil, ErrInvalidFrameNumber
+	}
+
+	offset := frameNumber * FrameSize
+	return image[offset : offset+FrameSize : offset+FrameSize], nil
+}
+
+// putRawImage encodes the card as raw image into b, which must hold MemoryCardTotalSize bytes.
+func (mc *MemoryCard) putRawImage(b []byte) {
+	mc.Header.put(b[headerOffset:])
+	for i := range mc.DirectoryFrames {
+		mc.DirectoryFrames[i].put(b[directoryOffset+i*FrameSize:])
+	}
+	for i := range mc.BrokenSelectors {
+		mc.BrokenSelectors[i].put(b[brokenSelectorOffset+i*FrameSize:])
+	}
+	for i := range mc.BrokenSelectorReplacements {
+		copy(b[replacementOffset+i*FrameSize:], mc.BrokenSelectorReplacements[i].Date[:])
+	}
+	for i := range mc.UnusedFrames {
+		copy(b[unusedFramesOffset+i*FrameSize:], mc.UnusedFrames[i][:])
+	}
+	copy(b[writeTestFrameOffset:], mc.WriteTestFrame[:])
+	for i := range mc.Blocks {
+		mc.Blocks[i].put(b[blocksOffset+i*BlockSize:])
+	}
+}
+
+// loadRawImage decodes the card from the raw image in b, which must hold MemoryCardTotalSize bytes.
+func (mc *MemoryCard) loadRawImage(b []byte) {
+	mc.Header.load(b[headerOffset:])
+	for i := range mc.DirectoryFrames {
+		mc.DirectoryFrames[i].load(b[directoryOffset+i*FrameSize:])
+	}
+	for i := range mc.BrokenSelectors {
+		mc.BrokenSelectors[i].load(b[brokenSelectorOffset+i*FrameSize:])
+	}
+	for i := range mc.BrokenSelectorReplacements {
+		copy(mc.BrokenSelectorReplacements[i].Date[:], b[replacementOffset+i*FrameSize:])
+	}
+	for i := range mc.UnusedFrames {
+		copy(mc.UnusedFrames[i][:], b[unusedFramesOffset+i*FrameSize:])
+	}
+	copy(mc.WriteTestFrame[:], b[writeTestFrameOffset:])
+	for i := range mc.Blocks {
+		mc.Blocks[i].load(b[blocksOffset+i*BlockSize:])
+	}
+}
+
+// MarshalBinary encodes the header frame.
+func (h *HeaderFrame) MarshalBinary() ([]byte, error) {
+	b := make([]byte, FrameSize)
+	h.put(b)
+	return b, nil
+}
+
+// UnmarshalBinary decodes the header frame.
+func (h *HeaderFrame) UnmarshalBinary(data []byte) error {
+	if len(data) != FrameSize {
+		return ErrInvalidFrameSize
+	}
+	h.load(data)
+	return nil
+}
+
+func (h *HeaderFrame) put(b []byte) {
+	copy(b[0:2], h.MagicBytes[:])
+	copy(b[2:checksumOffset], h.Unused[:])
+	b[checksumOffset] = h.Checksum
+}
+
+func (h *HeaderFrame) load(b []byte) {
+	copy(h.MagicBytes[:], b[0:2])
+	copy(h.Unused[:], b[2:checksumOffset])
+	h.Checksum = b[checksumOffset]
+}
+
+// MarshalBinary encodes the directory frame.
+func (df *DirectoryFrame) MarshalBinary() ([]byte, error) {
+	b := make([]byte, FrameSize)
+	df.put(b)
+	return b, nil
+}
+
+// UnmarshalBinary decodes the directory frame.
+func (df *DirectoryFrame) UnmarshalBinary(data []byte) error {
+	if len(data) != FrameSize {
+		return ErrInvalidFrameSize
+	}
+	df.load(data)
+	return nil
+}
+
+func (df *DirectoryFrame) put(b []byte) {
+	binary.LittleEndian.PutUint32(b[0x00:], uint32(df.BlockAllocationState))
+	binary.LittleEndian.PutUint32(b[0x04:], df.FileSize)
+	binary.LittleEndian.PutUint16(b[0x08:], df.NextBlock)
+	copy(b[directoryFrameNameOffset:directoryFrameZeroOffset], df.FileName[:])
+	b[directoryFrameZeroOffset] = df.Zero
+	copy(b[directoryFrameReservedOffset:checksumOffset], df.Reserved[:])
+	b[checksumOffset] = df.Checksum
+}
+
+func (df *DirectoryFrame) load(b []byte) {
+	df.BlockAllocationState = BlockAllocationState(binary.LittleEndian.Uint32(b[0x00:]))
+	df.FileSize = binary.LittleEndian.Uint32(b[0x04:])
+	df.NextBlock = binary.LittleEndian.Uint16(b[0x08:])
+	copy(df.FileName[:], b[directoryFrameNameOffset:directoryFrameZeroOffset])
+	df.Zero = b[directoryFrameZeroOffset]
+	copy(df.Reserved[:], b[directoryFrameReservedOffset:checksumOffset])
+	df.Checksum = b[checksumOffset]
+}
+
+// MarshalBinary encodes the broken selector frame.
+func (s *BrokenSelector) MarshalBinary() ([]byte, error) {
+	b := make([]byte, FrameSize)
+	s.put(b)
+	return b, nil
+}
+
+// UnmarshalBinary decodes the broken selector frame.
+func (s *BrokenSelector) UnmarshalBinary(data []byte) error {
+	if len(data) != FrameSize {
+		return ErrInvalidFrameSize
+	}
+	s.load(data)
+	return nil
+}
+
+func (s *BrokenSelector) put(b []byte) {
+	binary.LittleEndian.PutUint32(b[0x00:], s.BrokenSectorNumber)
+	copy(b[0x04:checksumOffset], s.Reserved[:])
+	b[checksumOffset] = s.Checksum
+}
+
+func (s *BrokenSelector) load(b []byte) {
+	s.BrokenSectorNumber = binary.LittleEndian.Uint32(b[0x00:])
+	copy(s.Reserved[:], b[0x04:checksumOffset])
+	s.Checksum = b[checksumOffset]
+}
+
+// MarshalBinary encodes the title frame.
+func (tf *BlockTitleFrame) MarshalBinary() ([]byte, error) {
+	b := make([]byte, FrameSize)
+	tf.put(b)
+	return b, nil
+}
+
+// UnmarshalBinary decodes the title frame.
+func (tf *BlockTitleFrame) UnmarshalBinary(data []byte) error {
+	if len(data) != FrameSize {
+		return ErrInvalidFrameSize
+	}
+	tf.load(data)
+	return nil
+}
+
+func (tf *BlockTitleFrame) put(b []byte) {
+	copy(b[0:2], tf.ID[:])
+	b[0x02] = byte(tf.IconDisplayFlag)
+	b[0x03] = tf.BlockNumber
+	copy(b[titleFrameTitleOffset:titleFrameReservedOffset], tf.Title.Data[:])
+	copy(b[titleFrameReservedOffset:titleFramePaletteOffset], tf.Reserved[:])
+	for i, color := range tf.IconColorPalette {
+		binary.LittleEndian.PutUint16(b[titleFramePaletteOffset+i*2:], color)
+	}
+}
+
+func (tf *BlockTitleFrame) load(b []byte) {
+	copy(tf.ID[:], b[0:2])
+	tf.IconDisplayFlag = IconDisplayFlag(b[0x02])
+	tf.BlockNumber = b[0x03]
+	copy(tf.Title.Data[:], b[titleFrameTitleOffset:titleFrameReservedOffset])
+	copy(tf.Reserved[:], b[titleFrameReservedOffset:titleFramePaletteOffset])
+	for i := range tf.IconColorPalette {
+		tf.IconColorPalette[i] = binary.LittleEndian.Uint16(b[titleFramePaletteOffset+i*2:])
+	}
+}
+
+// MarshalBinary encodes the block.
+func (b *Block) MarshalBinary() ([]byte, error) {
+	data := make([]byte, BlockSize)
+	b.put(data)
+	return data, nil
+}
+
+// UnmarshalBinary decodes the block.
+func (b *Block) UnmarshalBinary(data []byte) error {
+	if len(data) != BlockSize {
+		return ErrInvalidSaveSize
+	}
+	b.load(data)
+	return nil
+}
+
+func (b *Block) put(data []byte) {
+	b.TitleFrame.put(data)
+	for i := range b.IconFrames {
+		copy(data[iconFramesOffset+i*FrameSize:], b.IconFrames[i][:])
+	}
+	for i := range b.Data {
+		copy(data[dataFramesOffset+i*FrameSize:], b.Data[i][:])
+	}
+}
+
+func (b *Block) load(data []byte) {
+	b.TitleFrame.load(data)
+	for i := range b.IconFrames {
+		copy(b.IconFrames[i][:], data[iconFramesOffset+i*FrameSize:])
+	}
+	for i := range b.Data {
+		copy(b.Data[i][:], data[dataFramesOffset+i*FrameSize:])
+	}
+}
@@ -0,0 +1,160 @@
+package memcard
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"os"
+	"testing"
+)
+
+// sections returns pointers to the parts of the card in the order they are stored on the card,
+// to encode and decode the card with encoding/binary as reference.
+func (mc *MemoryCard) sections() []any {
+	return []any{
+		&mc.Header,
+		&mc.DirectoryFrames,
+		&mc.BrokenSelectors,
+		&mc.BrokenSelectorReplacements,
+		&mc.UnusedFrames,
+		&mc.WriteTestFrame,
+		&mc.Blocks,
+	}
+}
+
+func reflectDecode(t testing.TB, data []byte) *MemoryCard {
+	var card MemoryCard
+	reader := bytes.NewReader(data)
+	for _, section := range card.sections() {
+		if err := binary.Read(reader, binary.LittleEndian, section); err != nil {
+			t.Fatalf("Error decoding card: %v", err)
+		}
+	}
+	return &card
+}
+
+func reflectEncode(t testing.TB, card *MemoryCard) []byte {
+	var buffer bytes.Buffer
+	for _, section := range card.sections() {
+		if err := binary.Write(&buffer, binary.LittleEndian, section); err != nil {
+			t.Fatalf("Error encoding card: %v", err)
+		}
+	}
+	return buffer.Bytes()
+}
+
+func TestRawImage_MatchesReflection(t *testing.T) {
+	fixture, err := os.ReadFile("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error reading card: %v", err)
+	}
+
+	random := make([]byte, MemoryCardTotalSize)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range random {
+		random[i] = byte(rng.Uint32())
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "fixture", data: fixture},
+		{name: "random", data: random},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, err := readRawImage(tt.data)
+			if err != nil {
+				t.Fatalf("Error reading card: %v", err)
+			}
+			if *card != *reflectDecode(t, tt.data) {
+				t.Errorf("Expected decoded card to match encoding/binary")
+			}
+
+			data, err := card.encodeRawImage()
+			if err != nil {
+				t.Fatalf("Error encoding card: %v", err)
+			}
+			if !bytes.Equal(data, tt.data) || !bytes.Equal(data, reflectEncode(t, card)) {
+				t.Errorf("Expected encoded card to match encoding/binary")
+			}
+		})
+	}
+}
+
+func TestImageFrame(t *testing.T) {
+	data, err := NewFormattedMemoryCard().encodeRawImage()
+	if err != nil {
+		t.Fatalf("Error encoding card: %v", err)
+	}
+
+	frame, err := ImageFrame(data, FirstDirectoryFrameIndex)
+	if err != nil {
+		t.Fatalf("Error reading frame: %v", err)
+	}
+	frame[0] = byte(BlockAllocationStateInUseFirstOnlyBlock)
+
+	card, err := readRawImage(data)
+	if err != nil {
+		t.Fatalf("Error reading card: %v", err)
+	}
+	if card.DirectoryFrames[0].BlockAllocationState != BlockAllocationStateInUseFirstOnlyBlock {
+		t.Errorf("Expected frame to share its memory with the image")
+	}
+
+	if _, err := ImageFrame(data, NumFrames); err != ErrInvalidFrameNumber {
+		t.Errorf("Expected ErrInvalidFrameNumber, but got: %v", err)
+	}
+}
+
+func benchmarkFixture(b *testing.B) []byte {
+	data, err := os.ReadFile("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		b.Fatalf("Error reading card: %v", err)
+	}
+	return data
+}
+
+func BenchmarkReadRawImage(b *testing.B) {
+	data := benchmarkFixture(b)
+	b.SetBytes(MemoryCardTotalSize)
+	for b.Loop() {
+		if _, err := readRawImage(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadRawImage_Reflection(b *testing.B) {
+	data := benchmarkFixture(b)
+	b.SetBytes(MemoryCardTotalSize)
+	for b.Loop() {
+		reflectDecode(b, data)
+	}
+}
+
+func BenchmarkEncodeRawImage(b *testing.B) {
+	card, err := readRawImage(benchmarkFixture(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(MemoryCardTotalSize)
+	for b.Loop() {
+		if _, err := card.encodeRawImage(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeRawImage_Reflection(b *testing.B) {
+	card, err := readRawImage(benchmarkFixture(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(MemoryCardTotalSize)
+	for b.Loop() {
+		reflectEncode(b, card)
+	}
+}
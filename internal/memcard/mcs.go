@@ -0,0 +1,139 @@
+package memcard
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Single save .mcs files (PSXGameEdit, MemcardRex) hold the 128 byte directory frame of the
+// first block of the save, followed by the save blocks. Raw single saves hold only the save
+// blocks, the file name of the save is used as the name of the file.
+const MCSHeaderSize = FrameSize
+
+// mcsCodec reads and writes .mcs single saves.
+type mcsCodec struct{}
+
+func (mcsCodec) Format() SaveFormat {
+	return SaveFormatMCS
+}
+
+func (mcsCodec) Extensions() []string {
+	return []string{".mcs", ".ps1"}
+}
+
+func (mcsCodec) Detect(data []byte) bool {
+	return len(data) > MCSHeaderSize && (len(data)-MCSHeaderSize)%BlockSize == 0 &&
+		BlockAllocationState(binary.LittleEndian.Uint32(data)) == BlockAllocationStateInUseFirstOnlyBlock
+}
+
+func (mcsCodec) Decode(data []byte, _ string) (*Save, error) {
+	return decodeMCS(data)
+}
+
+func (mcsCodec) Encode(save *Save) ([]byte, error) {
+	return encodeMCS(save)
+}
+
+// ExportSave exports the file stored at blockIndex as .mcs single save.
+// blockIndex may point to any block of the file, the whole block chain is exported.
+func (mc *MemoryCard) ExportSave(blockIndex int) ([]byte, error) {
+	save, err := mc.ExtractSave(blockIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeMCS(save)
+}
+
+// ImportSave imports a .mcs single save into the first free blocks of the card and returns
+// the blocks it was written to.
+func (mc *MemoryCard) ImportSave(r io.Reader) ([]int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	save, err := decodeMCS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return mc.InsertSave(save)
+}
+
+// ExportRawSave exports the blocks of the file stored at blockIndex without any header.
+// The returned file name of the save should be used as the name of the exported file.
+func (mc *MemoryCard) ExportRawSave(blockIndex int) (string, []byte, error) {
+	save, err := mc.ExtractSave(blockIndex)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := save.Data()
+	if err != nil {
+		return "", nil, err
+	}
+
+	fileName := save.FileName()
+	return fileName.String(), data, nil
+}
+
+// ImportRawSave imports the blocks of a raw single save into the first free blocks of the card,
+// using the name of the imported file as file name of the save.
+func (mc *MemoryCard) ImportRawSave(fileName string, r io.Reader) ([]int, error) {
+	name, err := NewFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := decodeBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return mc.InsertSave(NewSave(name, blocks))
+}
+
+func encodeMCS(save *Save) ([]byte, error) {
+	blocks, err := save.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	// The directory frame describes the save as a file of its own
+	frame := save.DirectoryFrame
+	frame.BlockAllocationState = BlockAllocationStateInUseFirstOnlyBlock
+	frame.FileSize = uint32(len(save.Blocks) * BlockSize)
+	frame.NextBlock = NoNextBlock
+	frame.Checksum = calculateDirectoryFrameChecksum(&frame)
+
+	data := make([]byte, MCSHeaderSize, MCSHeaderSize+len(blocks))
+	frame.put(data)
+	return append(data, blocks...), nil
+}
+
+func decodeMCS(data []byte) (*Save, error) {
+	if len(data) <= MCSHeaderSize {
+		return nil, fmt.Errorf("%w: not a MCS file", ErrInvalidSave)
+	}
+
+	var frame DirectoryFrame
+	frame.load(data)
+
+	if frame.BlockAllocationState != BlockAllocationStateInUseFirstOnlyBlock {
+		return nil, fmt.Errorf("%w: MCS directory frame has state 0x%02X", ErrInvalidSave, uint32(frame.BlockAllocationState))
+	}
+
+	blocks, err := decodeBlocks(data[MCSHeaderSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Save{DirectoryFrame: frame, Blocks: blocks}, nil
+}
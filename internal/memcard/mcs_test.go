@@ -0,0 +1,63 @@
+package memcard
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMCS_RoundTrip(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BASLUS-00892FF7", 6, 2)
+
+	data, err := source.ExportSave(6)
+	if err != nil {
+		t.Fatalf("Error exporting save: %v", err)
+	}
+	if len(data) != MCSHeaderSize+2*BlockSize {
+		t.Fatalf("Expected %d bytes, but got: %d", MCSHeaderSize+2*BlockSize, len(data))
+	}
+
+	target := NewFormattedMemoryCard()
+	chain, err := target.ImportSave(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error importing save: %v", err)
+	}
+
+	if len(chain) != 2 || chain[0] != 0 || chain[1] != 1 {
+		t.Errorf("Expected save to be imported to blocks [0 1], but got: %v", chain)
+	}
+	if name := target.DirectoryFrames[0].FileName.String(); name != "BASLUS-00892FF7" {
+		t.Errorf("Expected file name BASLUS-00892FF7, but got: %q", name)
+	}
+	if marker := target.Blocks[1].Data[0][0]; marker != 2 {
+		t.Errorf("Expected second block to hold chain position 2, but got: %d", marker)
+	}
+	if report := target.Validate(); !report.Clean() {
+		t.Errorf("Expected card to validate after import, but got: %v", report.Issues)
+	}
+}
+
+func TestRawSave_RoundTrip(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BESCES-00344SAVE", 4)
+
+	name, data, err := source.ExportRawSave(4)
+	if err != nil {
+		t.Fatalf("Error exporting save: %v", err)
+	}
+	if name != "BESCES-00344SAVE" || len(data) != BlockSize {
+		t.Fatalf("Expected BESCES-00344SAVE with %d bytes, but got: %q with %d bytes", BlockSize, name, len(data))
+	}
+
+	target := NewFormattedMemoryCard()
+	if _, err := target.ImportRawSave(name, bytes.NewReader(data)); err != nil {
+		t.Fatalf("Error importing save: %v", err)
+	}
+	if target.DirectoryFrames[0].FileName != source.DirectoryFrames[4].FileName {
+		t.Errorf("Expected file name to be taken from the file, but got: %q", target.DirectoryFrames[0].FileName.String())
+	}
+
+	if _, err := target.ImportRawSave(name, bytes.NewReader(data[:100])); err != ErrInvalidSaveSize {
+		t.Errorf("Expected ErrInvalidSaveSize, but got: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package memcard
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The PS3 exports single PS1 saves as signed .PSV files. The save blocks follow a 0x84 byte header:
+//
+//	0x00-0x03  "\x00VSP"
+//	0x08-0x1B  signature seed
+//	0x1C-0x2F  signature (see sign.go)
+//	0x38-0x3B  0x14 (size of the PS1 part of the header)
+//	0x3C-0x3F  save type (1 = PS1)
+//	0x40-0x43  save size
+//	0x44-0x47  offset of the save data (0x84)
+//	0x48-0x4B  0x200
+//	0x5C-0x5F  save size
+//	0x60-0x63  0x03900000
+//	0x64-0x77  file name (product code)
+//	0x84-...   save blocks
+const (
+	PSVHeaderSize      = 0x84
+	psvSeedOffset      = 0x08
+	psvSignatureOffset = 0x1C
+	psvSaveTypePS1     = 1
+)
+
+var psvMagic = []byte("\x00VSP")
+
+// psvHeader is the header of a PS1 save in a .PSV file.
+type psvHeader struct {
+	Magic      [4]byte
+	_          uint32
+	Seed       [signatureSeedSize]byte
+	Signature  [signatureSize]byte
+	_          [2]uint32
+	HeaderSize uint32
+	SaveType   uint32
+	SaveSize   uint32
+	DataOffset uint32
+	BlockSize  uint32
+	_          [4]uint32
+	DataSize   uint32
+	Unknown    uint32
+	FileName   [20]byte
+	_          [3]uint32
+}
+
+// psvCodec reads and writes PS3 .PSV saves.
+type psvCodec struct{}
+
+func (psvCodec) Format() SaveFormat {
+	return SaveFormatPSV
+}
+
+func (psvCodec) Extensions() []string {
+	return []string{".psv"}
+}
+
+func (psvCodec) Detect(data []byte) bool {
+	return len(data) > PSVHeaderSize && bytes.HasPrefix(data, psvMagic)
+}
+
+func (psvCodec) Decode(data []byte, _ string) (*Save, error) {
+	return decodePSV(data)
+}
+
+func (psvCodec) Encode(save *Save) ([]byte, error) {
+	return encodePSV(save)
+}
+
+// VerifyPSV checks the signature of a .PSV file.
+func VerifyPSV(data []byte) error {
+	if len(data) < PSVHeaderSize || !bytes.HasPrefix(data, psvMagic) {
+		return fmt.Errorf("%w: not a PSV file", ErrInvalidSave)
+	}
+
+	if !verifyPS1File(data, psvSeedOffset, psvSignatureOffset) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ExportPSV exports the file stored at blockIndex as a signed PS3 .PSV file.
+// blockIndex may point to any block of the file, the whole block chain is exported.
+func (mc *MemoryCard) ExportPSV(blockIndex int) ([]byte, error) {
+	save, err := mc.ExtractSave(blockIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodePSV(save)
+}
+
+// ImportPSV imports a PS3 .PSV file into the first free blocks of the card and returns
+// the blocks it was written to. The signature of the file is not checked, see VerifyPSV.
+func (mc *MemoryCard) ImportPSV(r io.Reader) ([]int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	save, err := decodePSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return mc.InsertSave(save)
+}
+
+func encodePSV(save *Save) ([]byte, error) {
+	blocks, err := save.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	header := psvHeader{
+		HeaderSize: 0x14,
+		SaveType:   psvSaveTypePS1,
+		SaveSize:   uint32(len(blocks)),
+		DataOffset: PSVHeaderSize,
+		BlockSize:  0x200,
+		DataSize:   uint32(len(blocks)),
+		Unknown:    0x03900000,
+	}
+	copy(header.Magic[:], psvMagic)
+	copy(header.FileName[:], save.DirectoryFrame.FileName[:])
+
+	var buffer bytes.Buffer
+	if err := binary.Write(&buffer, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	buffer.Write(blocks)
+
+	data := buffer.Bytes()
+	signPS1File(data, psvSeedOffset, psvSignatureOffset)
+	return data, nil
+}
+
+func decodePSV(data []byte) (*Save, error) {
+	if len(data) < PSVHeaderSize || !bytes.HasPrefix(data, psvMagic) {
+		return nil, fmt.Errorf("%w: not a PSV file", ErrInvalidSave)
+	}
+
+	var header psvHeader
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+
+	if header.SaveType != psvSaveTypePS1 {
+		return nil, fmt.Errorf("%w: PSV file does not hold a PS1 save", ErrInvalidSave)
+	}
+
+	if header.DataOffset != PSVHeaderSize || uint64(header.DataOffset)+uint64(header.SaveSize) != uint64(len(data)) {
+		return nil, fmt.Errorf("%w: PSV save size %d does not match the file size %d", ErrInvalidSave, header.SaveSize, len(data))
+	}
+
+	blocks, err := decodeBlocks(data[header.DataOffset:])
+	if err != nil {
+		return nil, err
+	}
+
+	var fileName FileName
+	copy(fileName[:], header.FileName[:])
+
+	return NewSave(fileName, blocks), nil
+}
@@ -0,0 +1,69 @@
+package memcard
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestPSV_RoundTrip(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BASLUS-00892FF7", 3, 8, 1)
+
+	data, err := source.ExportPSV(8)
+	if err != nil {
+		t.Fatalf("Error exporting save: %v", err)
+	}
+
+	if binary.Size(psvHeader{}) != PSVHeaderSize {
+		t.Fatalf("Expected PSV header size %d, but got: %d", PSVHeaderSize, binary.Size(psvHeader{}))
+	}
+	if len(data) != PSVHeaderSize+3*BlockSize {
+		t.Fatalf("Expected %d bytes, but got: %d", PSVHeaderSize+3*BlockSize, len(data))
+	}
+	if err := VerifyPSV(data); err != nil {
+		t.Fatalf("Expected a valid signature, but got: %v", err)
+	}
+	if expected := referencePS1Signature(data, psvSeedOffset, psvSignatureOffset); !bytes.Equal(data[psvSignatureOffset:psvSignatureOffset+0x14], expected) {
+		t.Errorf("Expected signature %X, but got: %X", expected, data[psvSignatureOffset:psvSignatureOffset+0x14])
+	}
+	if !bytes.HasPrefix(data[0x64:], []byte("BASLUS-00892FF7")) {
+		t.Errorf("Expected product code in the header, but got: %q", data[0x64:0x78])
+	}
+
+	target := NewFormattedMemoryCard()
+	writeTestFile(t, target, "BASLUS-00001AIRCOMB", 0)
+
+	chain, err := target.ImportPSV(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error importing save: %v", err)
+	}
+
+	expected, _ := source.ExtractSave(3)
+	imported, err := target.ExtractSave(chain[0])
+	if err != nil {
+		t.Fatalf("Error reading imported save: %v", err)
+	}
+	if imported.FileName() != expected.FileName() || len(imported.Blocks) != 3 {
+		t.Fatalf("Expected imported save %q with 3 blocks, but got: %q with %d blocks", expected.FileName(), imported.FileName(), len(imported.Blocks))
+	}
+	for pos := range imported.Blocks {
+		if marker := imported.Blocks[pos].Data[0][0]; marker != byte(pos+1) {
+			t.Errorf("Expected block %d to hold chain position %d, but got: %d", pos, pos+1, marker)
+		}
+	}
+	if report := target.Validate(); !report.Clean() {
+		t.Errorf("Expected card to validate after import, but got: %v", report.Issues)
+	}
+}
+
+func TestVerifyPSV_ConsoleSamples(t *testing.T) {
+	for i, data := range consoleSamples(t, ".PSV") {
+		if err := VerifyPSV(data); err != nil {
+			t.Errorf("Expected console sample %d to verify, but got: %v", i, err)
+		}
+		if expected := referencePS1Signature(data, psvSeedOffset, psvSignatureOffset); !bytes.Equal(data[psvSignatureOffset:psvSignatureOffset+0x14], expected) {
+			t.Errorf("Expected reference signature %X for console sample %d", expected, i)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package memcard
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// rawCardCodec reads and writes plain 128 KB memory card images, as used by most emulators.
+type rawCardCodec struct{}
+
+func (rawCardCodec) Format() CardFormat {
+	return CardFormatRaw
+}
+
+func (rawCardCodec) Extensions() []string {
+	return []string{".mcr", ".mcd", ".mc", ".srm", ".ps", ".psm", ".ddf", ".mci", ".vm1", ".bin"}
+}
+
+func (rawCardCodec) Detect(data []byte) bool {
+	return len(data) == MemoryCardTotalSize
+}
+
+func (rawCardCodec) Decode(data []byte) (*MemoryCard, error) {
+	return readRawImage(data)
+}
+
+func (rawCardCodec) Encode(card *MemoryCard) ([]byte, error) {
+	return card.encodeRawImage()
+}
+
+// rawSaveCodec reads and writes single saves without any header (Xploder and others).
+// The file name of the save is stored as the name of the file.
+type rawSaveCodec struct{}
+
+func (rawSaveCodec) Format() SaveFormat {
+	return SaveFormatRaw
+}
+
+func (rawSaveCodec) Extensions() []string {
+	return []string{".mcb", ".mcx", ".pda"}
+}
+
+func (rawSaveCodec) Detect(data []byte) bool {
+	return len(data) > 0 && len(data)%BlockSize == 0
+}
+
+func (rawSaveCodec) Decode(data []byte, fileName string) (*Save, error) {
+	name := filepath.Base(fileName)
+	saveName, err := NewFileName(strings.TrimSuffix(name, filepath.Ext(name)))
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := decodeBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSave(saveName, blocks), nil
+}
+
+func (rawSaveCodec) Encode(save *Save) ([]byte, error) {
+	return save.Data()
+}
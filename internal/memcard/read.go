@@ -1,9 +1,8 @@
 package memcard
 
 import (
-	"encoding/binary"
 	"errors"
-	"os"
+	"io"
 )
 
 var (
@@ -11,32 +10,36 @@ var (
 	ErrEmptyFile             = errors.New("file is empty")
 )
 
+// Open reads a memory card image in any format of the default codec registry.
 func Open(filePath string) (*MemoryCard, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
-	}
+	return DefaultCodecRegistry().Open(filePath)
+}
 
-	defer file.Close()
+// Read reads a memory card image in any format of the default codec registry.
+// Formats that share the same size and magic bytes can not be told apart without a file name,
+// use CodecRegistry.Read to pass one.
+func Read(r io.Reader) (*MemoryCard, error) {
+	return DefaultCodecRegistry().Read(r, "")
+}
 
-	fileInfo, err := file.Stat()
+// UnmarshalBinary decodes a memory card image in any format of the default codec registry.
+func (mc *MemoryCard) UnmarshalBinary(data []byte) error {
+	card, err := DefaultCodecRegistry().DecodeCard(data, "")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if fileInfo.Size() == 0 {
-		return nil, ErrEmptyFile
-	}
+	*mc = *card
+	return nil
+}
 
-	if fileInfo.Size() != MemoryCardTotalSize {
+// readRawImage decodes a plain 128 KB memory card image.
+func readRawImage(data []byte) (*MemoryCard, error) {
+	if len(data) != MemoryCardTotalSize {
 		return nil, ErrInvalidMemoryCardSize
 	}
 
 	var memCard MemoryCard
-	err = binary.Read(file, binary.LittleEndian, &memCard)
-	if err != nil {
-		return nil, err
-	}
-
+	memCard.loadRawImage(data)
 	return &memCard, nil
 }
@@ -0,0 +1,58 @@
+package memcard
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestRead_WriteTo_RoundTrip(t *testing.T) {
+	raw, err := os.ReadFile("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error reading card: %v", err)
+	}
+
+	card, err := Read(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Error reading card: %v", err)
+	}
+
+	var buffer bytes.Buffer
+	n, err := card.WriteTo(&buffer)
+	if err != nil {
+		t.Fatalf("Error writing card: %v", err)
+	}
+	if n != int64(len(raw)) || !bytes.Equal(buffer.Bytes(), raw) {
+		t.Errorf("Expected written card to match the read card")
+	}
+}
+
+func TestMarshalBinary_RoundTrip(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 2, 5)
+	if err := card.SetFormat(CardFormatDexDrive); err != nil {
+		t.Fatalf("Error setting format: %v", err)
+	}
+
+	data, err := card.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error marshaling card: %v", err)
+	}
+
+	var loaded MemoryCard
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Error unmarshaling card: %v", err)
+	}
+	if loaded.Format() != CardFormatDexDrive {
+		t.Errorf("Expected format %s, but got: %s", CardFormatDexDrive, loaded.Format())
+	}
+	if loaded.DirectoryFrames != card.DirectoryFrames || loaded.Blocks != card.Blocks {
+		t.Errorf("Expected unmarshaled card to match the marshaled card")
+	}
+}
+
+func TestRead_Empty(t *testing.T) {
+	if _, err := Read(bytes.NewReader(nil)); err != ErrEmptyFile {
+		t.Errorf("Expected ErrEmptyFile, but got: %v", err)
+	}
+}
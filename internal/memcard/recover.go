@@ -0,0 +1,129 @@
+package memcard
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrDeletedFileNotRecoverable = errors.New("deleted file is not recoverable")
+	ErrBlockReused               = errors.New("block of deleted file has been reused")
+)
+
+// DeletedFile describes a deleted file whose blocks are still intact on the card.
+type DeletedFile struct {
+	FirstBlock int
+	Blocks     []int
+	FileName   FileName
+	Title      string
+}
+
+// deletedChain resolves the block chain of the deleted file starting at first and
+// verifies that it can still be restored.
+func (mc *MemoryCard) deletedChain(first int) ([]int, error) {
+	if first < 0 || first >= NumBlocks {
+		return nil, ErrInvalidBlockIndex
+	}
+
+	if mc.DirectoryFrames[first].BlockAllocationState != BlockAllocationStateFreeDeletedFirst {
+		return nil, fmt.Errorf("%w: block %d is not the first block of a deleted file", ErrDeletedFileNotRecoverable, first)
+	}
+
+	chain, err := mc.walkChain(first)
+	for _, idx := range chain {
+		if mc.DirectoryFrames[idx].BlockAllocationState.IsInUse() {
+			return nil, fmt.Errorf("%w: block %d", ErrBlockReused, idx)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeletedFileNotRecoverable, err)
+	}
+
+	// A block taken over by a file that was deleted later looks deleted again, but holds the data of the other file
+	if idx, shared := mc.sharedBlock(first, chain); shared {
+		return nil, fmt.Errorf("%w: block %d belongs to another file", ErrBlockReused, idx)
+	}
+
+	if err := mc.checkChainStates(chain,
+		BlockAllocationStateFreeDeletedFirst,
+		BlockAllocationStateFreeDeletedMiddle,
+		BlockAllocationStateFreeDeletedLast,
+	); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeletedFileNotRecoverable, err)
+	}
+
+	if size := mc.DirectoryFrames[first].FileSize; size != uint32(len(chain)*BlockSize) {
+		return nil, fmt.Errorf("%w: file size %d does not match %d blocks", ErrDeletedFileNotRecoverable, size, len(chain))
+	}
+
+	if block := mc.readBlock(first); !block.TitleFrame.HasMagic() {
+		return nil, fmt.Errorf("%w: title frame of block %d is damaged", ErrDeletedFileNotRecoverable, first)
+	}
+
+	return chain, nil
+}
+
+// sharedBlock returns a block of the chain starting at first that the chain of another stored or deleted file links to as well.
+func (mc *MemoryCard) sharedBlock(first int, chain []int) (int, bool) {
+	for other := range NumBlocks {
+		state := mc.DirectoryFrames[other].BlockAllocationState
+		if other == first || (state != BlockAllocationStateInUseFirstOnlyBlock && state != BlockAllocationStateFreeDeletedFirst) {
+			continue
+		}
+
+		otherChain, _ := mc.walkChain(other)
+		for _, idx := range otherChain {
+			if slices.Contains(chain, idx) {
+				return idx, true
+			}
+		}
+	}
+	return NoBlockIndex, false
+}
+
+// ListDeletedFiles returns all deleted files that can still be restored.
+// A deleted file is recoverable when its block chain is complete, none of its blocks
+// has been reused, not even by a file that was deleted as well, and its title frame is still intact.
+func (mc *MemoryCard) ListDeletedFiles() []DeletedFile {
+	files := []DeletedFile{}
+
+	for i := range NumBlocks {
+		if mc.DirectoryFrames[i].BlockAllocationState != BlockAllocationStateFreeDeletedFirst {
+			continue
+		}
+
+		chain, err := mc.deletedChain(i)
+		if err != nil {
+			continue
+		}
+
+		block := mc.readBlock(i)
+		files = append(files, DeletedFile{
+			FirstBlock: i,
+			Blocks:     chain,
+			FileName:   mc.DirectoryFrames[i].FileName,
+			Title:      block.TitleFrame.Title.String(),
+		})
+	}
+
+	return files
+}
+
+// RestoreDeletedFile restores the deleted file starting at blockIndex.
+// The blocks are marked as in use again (0x51, 0x52, 0x53) and their directory frame
+// checksums are recalculated. Restoring is refused if any block of the file has been reused.
+func (mc *MemoryCard) RestoreDeletedFile(blockIndex int) error {
+	chain, err := mc.deletedChain(blockIndex)
+	if err != nil {
+		return err
+	}
+
+	mc.linkChain(chain,
+		BlockAllocationStateInUseFirstOnlyBlock,
+		BlockAllocationStateInUseMiddleBlock,
+		BlockAllocationStateInUseLastBlock,
+	)
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package memcard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRestoreDeletedFile(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 1, 4, 3)
+	expected := card.DirectoryFrames
+
+	if err := card.DeleteBlockFrom(1); err != nil {
+		t.Fatalf("Error deleting file: %v", err)
+	}
+
+	files := card.ListDeletedFiles()
+	if len(files) != 1 || files[0].FirstBlock != 1 || len(files[0].Blocks) != 3 {
+		t.Fatalf("Expected one recoverable file with 3 blocks at block 1, but got: %+v", files)
+	}
+
+	if err := card.RestoreDeletedFile(1); err != nil {
+		t.Fatalf("Error restoring file: %v", err)
+	}
+
+	if card.DirectoryFrames != expected {
+		t.Errorf("Expected directory frames to match the state before deletion")
+	}
+	if len(card.ListDeletedFiles()) != 0 {
+		t.Errorf("Expected no recoverable files after restoring")
+	}
+}
+
+func TestRestoreDeletedFile_BlockReused(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 0, 1, 2)
+
+	if err := card.DeleteBlockFrom(0); err != nil {
+		t.Fatalf("Error deleting file: %v", err)
+	}
+
+	// A new save takes over the middle block of the deleted file
+	writeTestFile(t, card, "BASLUS-00001AIRCOMB", 1)
+
+	if files := card.ListDeletedFiles(); len(files) != 0 {
+		t.Errorf("Expected no recoverable files, but got: %+v", files)
+	}
+
+	if err := card.RestoreDeletedFile(0); !errors.Is(err, ErrBlockReused) {
+		t.Fatalf("Expected ErrBlockReused, but got: %v", err)
+	}
+}
+
+func TestRestoreDeletedFile_BlockReusedByDeletedFile(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 1, 2)
+	if err := card.DeleteBlockFrom(1); err != nil {
+		t.Fatalf("Error deleting file: %v", err)
+	}
+
+	// A second save takes over the last block of the first one and is deleted as well,
+	// so the block is marked as deleted last block again
+	writeTestFile(t, card, "BASLUS-00001AIRCOMB", 3, 2)
+	if err := card.DeleteBlockFrom(3); err != nil {
+		t.Fatalf("Error deleting file: %v", err)
+	}
+
+	if files := card.ListDeletedFiles(); len(files) != 0 {
+		t.Errorf("Expected no recoverable files, but got: %+v", files)
+	}
+	for _, first := range []int{1, 3} {
+		if err := card.RestoreDeletedFile(first); !errors.Is(err, ErrBlockReused) {
+			t.Errorf("Expected ErrBlockReused for the file at block %d, but got: %v", first, err)
+		}
+	}
+}
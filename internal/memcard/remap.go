@@ -0,0 +1,185 @@
+package memcard
+
+import (
+	"errors"
+)
+
+// The BIOS remaps frames that failed to write. The broken sector list in frames 16-35 of the
+// header block holds the card wide number (block*64 + frame) of up to 20 broken frames,
+// the frame holding the data of broken frame i is replacement frame i in frames 36-55.
+// Unused entries hold NoBrokenSector.
+//
+// Only frames of the save blocks (frame 64-1023) are looked up in the list,
+// the header block is always accessed directly.
+const (
+	FramesPerBlock = BlockSize / FrameSize
+	NumFrames      = MemoryCardTotalSize / FrameSize
+	NoBrokenSector = 0xFFFFFFFF
+)
+
+var ErrInvalidFrameNumber = errors.New("invalid frame number")
+
+// blockFrameNumber returns the card wide number of a frame of a save block.
+func blockFrameNumber(blockIndex, frame int) int {
+	return (blockIndex+1)*FramesPerBlock + frame
+}
+
+// replacementOf returns the index of the replacement frame of a broken frame,
+// or -1 if the frame is not remapped.
+func (mc *MemoryCard) replacementOf(frameNumber int) int {
+	for i := range mc.BrokenSelectors {
+		if sector := mc.BrokenSelectors[i].BrokenSectorNumber; sector != NoBrokenSector && int(sector) == frameNumber {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReadFrame returns the frame with the card wide frame number (64-1023).
+// Remapped frames are read from their replacement frame.
+func (mc *MemoryCard) ReadFrame(frameNumber int) ([FrameSize]byte, error) {
+	if frameNumber < FramesPerBlock || frameNumber >= NumFrames {
+		return [FrameSize]byte{}, ErrInvalidFrameNumber
+	}
+
+	if i := mc.replacementOf(frameNumber); i != -1 {
+		return mc.BrokenSelectorReplacements[i].Date, nil
+	}
+
+	blockIndex := frameNumber/FramesPerBlock - 1
+	frames := blockFrames(&mc.Blocks[blockIndex])
+	return frames[frameNumber%FramesPerBlock], nil
+}
+
+// WriteFrame writes the frame with the card wide frame number (64-1023).
+// Remapped frames are written to their replacement frame, the broken frame is left unchanged.
+func (mc *MemoryCard) WriteFrame(frameNumber int, data [FrameSize]byte) error {
+	if frameNumber < FramesPerBlock || frameNumber >= NumFrames {
+		return ErrInvalidFrameNumber
+	}
+
+	if i := mc.replacementOf(frameNumber); i != -1 {
+		mc.BrokenSelectorReplacements[i].Date = data
+		return nil
+	}
+
+	blockIndex := frameNumber/FramesPerBlock - 1
+	frames := blockFrames(&mc.Blocks[blockIndex])
+	frames[frameNumber%FramesPerBlock] = data
+	mc.Blocks[blockIndex] = blockFromFrames(&frames)
+	return nil
+}
+
+// ReadBlock returns the block at blockIndex with its remapped frames read from their replacement frames.
+func (mc *MemoryCard) ReadBlock(blockIndex int) (Block, error) {
+	if blockIndex < 0 || blockIndex >= NumBlocks {
+		return Block{}, ErrInvalidBlockIndex
+	}
+	return mc.readBlock(blockIndex), nil
+}
+
+// WriteBlock writes the block at blockIndex, remapped frames are written to their replacement frames.
+func (mc *MemoryCard) WriteBlock(blockIndex int, block Block) error {
+	if blockIndex < 0 || blockIndex >= NumBlocks {
+		return ErrInvalidBlockIndex
+	}
+	mc.writeBlock(blockIndex, block)
+	return nil
+}
+
+// RemappedFrames returns the card wide numbers of the frames of the block that are remapped.
+func (mc *MemoryCard) RemappedFrames(blockIndex int) []int {
+	remapped := []int{}
+	if blockIndex < 0 || blockIndex >= NumBlocks {
+		return remapped
+	}
+
+	for frame := range FramesPerBlock {
+		if frameNumber := blockFrameNumber(blockIndex, frame); mc.replacementOf(frameNumber) != -1 {
+			remapped = append(remapped, frameNumber)
+		}
+	}
+	return remapped
+}
+
+// IsRemapped reports whether any frame of the file the block belongs to is remapped.
+// Free blocks and blocks of broken chains only report their own frames.
+func (mc *MemoryCard) IsRemapped(blockIndex int) bool {
+	chain, err := mc.FileChain(blockIndex)
+	if err != nil {
+		chain = []int{blockIndex}
+	}
+
+	for _, idx := range chain {
+		if len(mc.RemappedFrames(idx)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRemappedFrames reports whether the broken sector list has any entry for a save block.
+func (mc *MemoryCard) hasRemappedFrames() bool {
+	for i := range mc.BrokenSelectors {
+		if sector := mc.BrokenSelectors[i].BrokenSectorNumber; sector != NoBrokenSector && sector >= FramesPerBlock && sector < NumFrames {
+			return true
+		}
+	}
+	return false
+}
+
+func (mc *MemoryCard) readBlock(blockIndex int) Block {
+	if !mc.hasRemappedFrames() {
+		return mc.Blocks[blockIndex]
+	}
+
+	frames := blockFrames(&mc.Blocks[blockIndex])
+	for frame := range frames {
+		if i := mc.replacementOf(blockFrameNumber(blockIndex, frame)); i != -1 {
+			frames[frame] = mc.BrokenSelectorReplacements[i].Date
+		}
+	}
+	return blockFromFrames(&frames)
+}
+
+func (mc *MemoryCard) writeBlock(blockIndex int, block Block) {
+	if !mc.hasRemappedFrames() {
+		mc.Blocks[blockIndex] = block
+		return
+	}
+
+	// The broken frames keep their old content, their data goes to the replacement frames
+	frames := blockFrames(&block)
+	current := blockFrames(&mc.Blocks[blockIndex])
+	for frame := range frames {
+		if i := mc.replacementOf(blockFrameNumber(blockIndex, frame)); i != -1 {
+			mc.BrokenSelectorReplacements[i].Date = frames[frame]
+			frames[frame] = current[frame]
+		}
+	}
+	mc.Blocks[blockIndex] = blockFromFrames(&frames)
+}
+
+// blockFrames returns the raw frames of a block.
+func blockFrames(block *Block) [FramesPerBlock][FrameSize]byte {
+	var data [BlockSize]byte
+	block.put(data[:])
+
+	var frames [FramesPerBlock][FrameSize]byte
+	for i := range frames {
+		copy(frames[i][:], data[i*FrameSize:])
+	}
+	return frames
+}
+
+// blockFromFrames decodes a block from its raw frames.
+func blockFromFrames(frames *[FramesPerBlock][FrameSize]byte) Block {
+	var data [BlockSize]byte
+	for i := range frames {
+		copy(data[i*FrameSize:], frames[i][:])
+	}
+
+	var block Block
+	block.load(data[:])
+	return block
+}
@@ -0,0 +1,76 @@
+package memcard
+
+import (
+	"testing"
+)
+
+// remapFrame marks a frame as broken and remaps it to the replacement frame at index.
+func remapFrame(card *MemoryCard, index, frameNumber int, data [FrameSize]byte) {
+	card.BrokenSelectors[index].BrokenSectorNumber = uint32(frameNumber)
+	card.BrokenSelectors[index].Checksum = calculateBrokenSelectorChecksum(&card.BrokenSelectors[index])
+	card.BrokenSelectorReplacements[index].Date = data
+}
+
+func TestReadBlock_Remapped(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 2, 3)
+
+	// Data frame 0 of block 3 is frame 4 of the block
+	replacement := [FrameSize]byte{0x42}
+	remapFrame(card, 0, blockFrameNumber(3, 4), replacement)
+
+	block, err := card.ReadBlock(3)
+	if err != nil {
+		t.Fatalf("Error reading block: %v", err)
+	}
+	if block.Data[0][0] != 0x42 {
+		t.Errorf("Expected remapped frame to be read from the replacement frame, but got: 0x%02X", block.Data[0][0])
+	}
+
+	save, err := card.ExtractSave(2)
+	if err != nil {
+		t.Fatalf("Error extracting save: %v", err)
+	}
+	if save.Blocks[1].Data[0][0] != 0x42 {
+		t.Errorf("Expected extracted save to hold the replacement frame")
+	}
+
+	if !card.IsRemapped(2) || !card.IsRemapped(3) {
+		t.Errorf("Expected all blocks of the file to be reported as remapped")
+	}
+	if card.IsRemapped(4) {
+		t.Errorf("Expected free block 4 not to be reported as remapped")
+	}
+}
+
+func TestInsertSave_Remapped(t *testing.T) {
+	source := NewFormattedMemoryCard()
+	writeTestFile(t, source, "BASLUS-00892FF7", 0)
+	save, err := source.ExtractSave(0)
+	if err != nil {
+		t.Fatalf("Error extracting save: %v", err)
+	}
+
+	// The title frame of block 0 is broken
+	target := NewFormattedMemoryCard()
+	target.Blocks[0].TitleFrame.ID = [2]byte{'X', 'X'}
+	remapFrame(target, 3, blockFrameNumber(0, 0), [FrameSize]byte{})
+
+	chain, err := target.InsertSave(save)
+	if err != nil {
+		t.Fatalf("Error inserting save: %v", err)
+	}
+	if chain[0] != 0 {
+		t.Fatalf("Expected save in block 0, but got: %v", chain)
+	}
+
+	if target.Blocks[0].TitleFrame.ID != [2]byte{'X', 'X'} {
+		t.Errorf("Expected broken frame to be left unchanged")
+	}
+	if frame, _ := target.ReadFrame(blockFrameNumber(0, 0)); frame[0] != 'S' || frame[1] != 'C' {
+		t.Errorf("Expected title frame to be written to the replacement frame, but got: %q", frame[:2])
+	}
+	if report := target.Validate(); !report.Valid() {
+		t.Errorf("Expected card to be valid, but got: %v", report.Issues)
+	}
+}
@@ -0,0 +1,206 @@
+package memcard
+
+import (
+	"fmt"
+)
+
+// RepairOptions controls how a memory card is repaired.
+type RepairOptions struct {
+	// DryRun reports the changes a repair would make without modifying the card.
+	DryRun bool
+}
+
+// RepairChange describes a single change made while repairing a memory card.
+// Frame and Block follow the same conventions as Issue.
+type RepairChange struct {
+	Code    IssueCode
+	Frame   int
+	Block   int
+	Message string
+}
+
+func (c RepairChange) String() string {
+	switch {
+	case c.Block != NoBlockIndex:
+		return fmt.Sprintf("block %d: %s", c.Block, c.Message)
+	case c.Frame != NoFrameIndex:
+		return fmt.Sprintf("frame %d: %s", c.Frame, c.Message)
+	}
+	return c.Message
+}
+
+// RepairReport lists the changes made by a repair and the issues that could not be fixed.
+type RepairReport struct {
+	DryRun    bool
+	Changes   []RepairChange
+	Remaining []Issue
+}
+
+func (r *RepairReport) add(code IssueCode, frame, block int, format string, args ...any) {
+	r.Changes = append(r.Changes, RepairChange{
+		Code:    code,
+		Frame:   frame,
+		Block:   block,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Repair fixes the issues Validate reports that can be fixed automatically:
+//   - block chains are truncated at loops, dangling links, cross-linked blocks and links to blocks
+//     that are not middle or last blocks, and the allocation states along each chain are corrected
+//   - the file size of each file is set to match its chain length
+//   - orphaned middle and last blocks are turned into deleted entries
+//   - header, directory frame and broken selector checksums are recalculated
+//   - the write test frame is rebuilt from the header frame
+//
+// Issues that cannot be repaired, like a damaged header magic or title frame, are listed in
+// RepairReport.Remaining. With RepairOptions.DryRun the card is left unchanged.
+func (mc *MemoryCard) Repair(options RepairOptions) *RepairReport {
+	report := &RepairReport{DryRun: options.DryRun}
+
+	// Work on a copy, so a dry run does not touch the card
+	work := *mc
+
+	var badChecksums [NumBlocks]bool
+	for i := range NumBlocks {
+		badChecksums[i] = work.DirectoryFrames[i].Checksum != calculateDirectoryFrameChecksum(&work.DirectoryFrames[i])
+	}
+
+	work.repairChains(report)
+	work.repairChecksums(report, badChecksums)
+
+	report.Remaining = work.Validate().Issues
+
+	if !options.DryRun {
+		*mc = work
+	}
+
+	return report
+}
+
+func (mc *MemoryCard) repairChains(report *RepairReport) {
+	owner := [NumBlocks]int{}
+	for i := range owner {
+		owner[i] = NoBlockIndex
+	}
+
+	for first := range NumBlocks {
+		if mc.DirectoryFrames[first].BlockAllocationState != BlockAllocationStateInUseFirstOnlyBlock {
+			continue
+		}
+
+		chain := []int{first}
+		owner[first] = first
+
+		for current := first; ; {
+			df := &mc.DirectoryFrames[current]
+			next := int(df.NextBlock)
+			if df.NextBlock == NoNextBlock {
+				break
+			}
+
+			var code IssueCode
+			var reason string
+			switch {
+			case next >= NumBlocks:
+				code, reason = IssueDanglingNextBlock, "does not exist"
+			case owner[next] == first:
+				code, reason = IssueChainLoop, "loops back into the chain"
+			case owner[next] != NoBlockIndex:
+				code, reason = IssueCrossLinkedBlock, fmt.Sprintf("is used by the file starting at block %d", owner[next])
+			case mc.DirectoryFrames[next].BlockAllocationState != BlockAllocationStateInUseMiddleBlock &&
+				mc.DirectoryFrames[next].BlockAllocationState != BlockAllocationStateInUseLastBlock:
+				code, reason = IssueChainState, fmt.Sprintf("has state 0x%02X", uint32(mc.DirectoryFrames[next].BlockAllocationState))
+			}
+
+			if code != "" {
+				report.add(code, FirstDirectoryFrameIndex+current, current,
+					"truncated file starting at block %d, next block %d %s", first, next, reason)
+				df.NextBlock = NoNextBlock
+				break
+			}
+
+			chain = append(chain, next)
+			owner[next] = first
+			current = next
+		}
+
+		for pos, idx := range chain {
+			df := &mc.DirectoryFrames[idx]
+			expected := chainState(pos, len(chain), BlockAllocationStateInUseFirstOnlyBlock, BlockAllocationStateInUseMiddleBlock, BlockAllocationStateInUseLastBlock)
+			if df.BlockAllocationState != expected {
+				report.add(IssueChainState, FirstDirectoryFrameIndex+idx, idx,
+					"changed state from 0x%02X to 0x%02X", uint32(df.BlockAllocationState), uint32(expected))
+				df.BlockAllocationState = expected
+			}
+		}
+
+		df := &mc.DirectoryFrames[first]
+		if expected := uint32(len(chain) * BlockSize); df.FileSize != expected {
+			report.add(IssueFileSizeMismatch, FirstDirectoryFrameIndex+first, first,
+				"changed file size from %d to %d bytes", df.FileSize, expected)
+			df.FileSize = expected
+		}
+	}
+
+	for i := range NumBlocks {
+		df := &mc.DirectoryFrames[i]
+		if owner[i] != NoBlockIndex {
+			continue
+		}
+
+		var deleted BlockAllocationState
+		switch df.BlockAllocationState {
+		case BlockAllocationStateInUseMiddleBlock:
+			deleted = BlockAllocationStateFreeDeletedMiddle
+		case BlockAllocationStateInUseLastBlock:
+			deleted = BlockAllocationStateFreeDeletedLast
+		default:
+			continue
+		}
+
+		report.add(IssueOrphanBlock, FirstDirectoryFrameIndex+i, i,
+			"marked orphaned block as deleted, changed state from 0x%02X to 0x%02X", uint32(df.BlockAllocationState), uint32(deleted))
+		df.BlockAllocationState = deleted
+	}
+}
+
+// repairChecksums recalculates all checksums and rebuilds the write test frame.
+// Directory frames changed by earlier repairs get a new checksum silently, badChecksums
+// marks the frames whose checksum was already wrong before the repair.
+func (mc *MemoryCard) repairChecksums(report *RepairReport, badChecksums [NumBlocks]bool) {
+	if expected := calculateHeaderChecksum(&mc.Header); mc.Header.Checksum != expected {
+		report.add(IssueHeaderChecksum, HeaderFrameIndex, NoBlockIndex,
+			"changed header checksum from 0x%02X to 0x%02X", mc.Header.Checksum, expected)
+		mc.Header.Checksum = expected
+	}
+
+	for i := range NumBlocks {
+		df := &mc.DirectoryFrames[i]
+		expected := calculateDirectoryFrameChecksum(df)
+		if df.Checksum == expected {
+			continue
+		}
+
+		if badChecksums[i] {
+			report.add(IssueDirectoryChecksum, FirstDirectoryFrameIndex+i, i,
+				"changed directory frame checksum from 0x%02X to 0x%02X", df.Checksum, expected)
+		}
+		df.Checksum = expected
+	}
+
+	for i := range mc.BrokenSelectors {
+		selector := &mc.BrokenSelectors[i]
+		if expected := calculateBrokenSelectorChecksum(selector); selector.Checksum != expected {
+			report.add(IssueBrokenSelectorChecksum, FirstBrokenSelectorFrameIndex+i, NoBlockIndex,
+				"changed broken selector checksum from 0x%02X to 0x%02X", selector.Checksum, expected)
+			selector.Checksum = expected
+		}
+	}
+
+	if expected := headerFrameBytes(&mc.Header); mc.WriteTestFrame != expected {
+		report.add(IssueWriteTestFrame, WriteTestFrameIndex, NoBlockIndex,
+			"rebuilt write test frame from the header frame")
+		mc.WriteTestFrame = expected
+	}
+}
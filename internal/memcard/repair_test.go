@@ -0,0 +1,62 @@
+package memcard
+
+import (
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 0, 1, 2)
+	writeTestFile(t, card, "BASLUS-00001AIRCOMB", 5, 6)
+
+	// Loop from the middle block back to the first block
+	card.DirectoryFrames[1].NextBlock = 0
+	// Orphaned last block and wrong file size
+	card.DirectoryFrames[5].NextBlock = NoNextBlock
+	// Broken checksums
+	card.Header.Checksum ^= 0xFF
+	card.DirectoryFrames[9].Checksum ^= 0xFF
+	card.BrokenSelectors[0].Checksum ^= 0xFF
+	card.WriteTestFrame[5] = 0x42
+
+	damaged := *card
+	dryRun := card.Repair(RepairOptions{DryRun: true})
+	if *card != damaged {
+		t.Fatalf("Expected dry run to leave the card unchanged")
+	}
+
+	report := card.Repair(RepairOptions{})
+	if len(report.Changes) != len(dryRun.Changes) {
+		t.Errorf("Expected dry run to report %d changes, but got: %d", len(report.Changes), len(dryRun.Changes))
+	}
+
+	expectedCodes := []IssueCode{
+		IssueChainLoop,
+		IssueChainState,
+		IssueFileSizeMismatch,
+		IssueOrphanBlock,
+		IssueHeaderChecksum,
+		IssueDirectoryChecksum,
+		IssueBrokenSelectorChecksum,
+		IssueWriteTestFrame,
+	}
+	for _, code := range expectedCodes {
+		found := false
+		for _, change := range report.Changes {
+			found = found || change.Code == code
+		}
+		if !found {
+			t.Errorf("Expected a %s change, but got: %v", code, report.Changes)
+		}
+	}
+
+	if len(report.Remaining) != 0 {
+		t.Errorf("Expected no remaining issues, but got: %v", report.Remaining)
+	}
+	if validation := card.Validate(); !validation.Clean() {
+		t.Errorf("Expected repaired card to validate, but got: %v", validation.Issues)
+	}
+	if state := card.DirectoryFrames[6].BlockAllocationState; state != BlockAllocationStateFreeDeletedLast {
+		t.Errorf("Expected orphaned block to be deleted, but got state: 0x%02X", uint32(state))
+	}
+}
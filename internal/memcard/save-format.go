@@ -0,0 +1,42 @@
+package memcard
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	ErrUnknownSaveFormat = errors.New("unknown single save format")
+)
+
+// SaveFormat identifies the file format of a single save.
+type SaveFormat string
+
+const (
+	SaveFormatMCS          SaveFormat = "mcs" // PSXGameEdit/MemcardRex save with a directory frame header (.mcs, .ps1)
+	SaveFormatPSV          SaveFormat = "psv" // signed PS3 save (.psv)
+	SaveFormatActionReplay SaveFormat = "psx" // Action Replay/GameShark save with a 54 byte header (.psx)
+	SaveFormatRaw          SaveFormat = "raw" // Xploder and other raw saves named after the save (.mcb, .mcx, .pda)
+)
+
+// SaveFormatFromFileName detects the single save format from the extension of the file name.
+func SaveFormatFromFileName(fileName string) (SaveFormat, error) {
+	codec, err := DefaultCodecRegistry().SaveCodecForFileName(fileName)
+	if err != nil {
+		return "", err
+	}
+	return codec.Format(), nil
+}
+
+// ExportSaveAs exports the file stored at blockIndex in the given single save format.
+// It returns the data and a suggested file name: the file name of the save with the extension of the format.
+func (mc *MemoryCard) ExportSaveAs(blockIndex int, format SaveFormat) (string, []byte, error) {
+	return DefaultCodecRegistry().ExportSave(mc, blockIndex, format)
+}
+
+// ImportSaveAs imports a single save into the first free blocks of the card and returns the blocks it
+// was written to. The format is detected from the content and the extension of the file name, raw saves
+// take the file name without extension as file name of the save.
+func (mc *MemoryCard) ImportSaveAs(fileName string, r io.Reader) ([]int, error) {
+	return DefaultCodecRegistry().ImportSave(mc, fileName, r)
+}
@@ -0,0 +1,71 @@
+package memcard
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExportSaveAs_RoundTrip(t *testing.T) {
+	source, err := Open("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error opening card: %v", err)
+	}
+
+	tests := []struct {
+		format   SaveFormat
+		fileName string
+		size     int
+	}{
+		{format: SaveFormatMCS, fileName: "BASLUS-00707SILENT00.mcs", size: MCSHeaderSize + BlockSize},
+		{format: SaveFormatPSV, fileName: "BASLUS-00707SILENT00.psv", size: PSVHeaderSize + BlockSize},
+		{format: SaveFormatActionReplay, fileName: "BASLUS-00707SILENT00.psx", size: ActionReplayHeaderSize + BlockSize},
+		{format: SaveFormatRaw, fileName: "BASLUS-00707SILENT00.mcb", size: BlockSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			fileName, data, err := source.ExportSaveAs(1, tt.format)
+			if err != nil {
+				t.Fatalf("Error exporting save: %v", err)
+			}
+			if fileName != tt.fileName || len(data) != tt.size {
+				t.Fatalf("Expected %s with %d bytes, but got: %s with %d bytes", tt.fileName, tt.size, fileName, len(data))
+			}
+
+			target := NewFormattedMemoryCard()
+			chain, err := target.ImportSaveAs(fileName, bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Error importing save: %v", err)
+			}
+
+			if target.DirectoryFrames[chain[0]].FileName != source.DirectoryFrames[1].FileName {
+				t.Errorf("Expected file name %q, but got: %q", source.DirectoryFrames[1].FileName.String(), target.DirectoryFrames[chain[0]].FileName.String())
+			}
+			if target.Blocks[chain[0]].TitleFrame.Title != source.Blocks[1].TitleFrame.Title {
+				t.Errorf("Expected save title to be kept")
+			}
+		})
+	}
+}
+
+func TestExportActionReplaySave_Title(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BESLES-01234GAME", 0)
+	title, err := NewShiftJISString("ＡＣＥＣＯＭＢＡＴ３　ｅｌｅｃｔｒｏｓｐｈｅｒｅ")
+	if err != nil {
+		t.Fatalf("Error encoding title: %v", err)
+	}
+	card.Blocks[0].TitleFrame.Title = title
+
+	data, err := card.ExportActionReplaySave(0)
+	if err != nil {
+		t.Fatalf("Error exporting save: %v", err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("BESLES-01234GAME\x00")) {
+		t.Errorf("Expected file name in the header, but got: %q", data[:actionReplayTitleOffset])
+	}
+	if !bytes.HasPrefix(data[actionReplayTitleOffset:], []byte("ACECOMBAT3 electrosphere\x00")) {
+		t.Errorf("Expected ASCII title in the header, but got: %q", data[actionReplayTitleOffset:ActionReplayHeaderSize])
+	}
+}
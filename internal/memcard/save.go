@@ -0,0 +1,149 @@
+package memcard
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidSave     = errors.New("invalid save")
+	ErrInvalidSaveSize = errors.New("invalid save size, expected a multiple of 8 Kilobytes")
+)
+
+// Save is a single file taken off a memory card: the directory frame of its first block and
+// the blocks of the file in chain order. Single save formats (.mcs, .psv, ...) are decoded into
+// and encoded from a Save.
+type Save struct {
+	DirectoryFrame DirectoryFrame
+	Blocks         []Block
+}
+
+// NewSave creates a save with the given file name and blocks.
+func NewSave(fileName FileName, blocks []Block) *Save {
+	save := &Save{Blocks: blocks}
+	save.DirectoryFrame.BlockAllocationState = BlockAllocationStateInUseFirstOnlyBlock
+	save.DirectoryFrame.FileSize = uint32(len(blocks) * BlockSize)
+	save.DirectoryFrame.NextBlock = NoNextBlock
+	save.DirectoryFrame.FileName = fileName
+	save.DirectoryFrame.Checksum = calculateDirectoryFrameChecksum(&save.DirectoryFrame)
+	return save
+}
+
+// FileName returns the file name of the save.
+func (s *Save) FileName() FileName {
+	return s.DirectoryFrame.FileName
+}
+
+// Title returns the title of the save, as stored in its title frame.
+func (s *Save) Title() string {
+	if len(s.Blocks) == 0 {
+		return ""
+	}
+	return s.Blocks[0].TitleFrame.Title.String()
+}
+
+// Data returns the raw data of all blocks of the save.
+func (s *Save) Data() ([]byte, error) {
+	return encodeBlocks(s.Blocks)
+}
+
+// encodeBlocks encodes blocks to their raw data.
+func encodeBlocks(blocks []Block) ([]byte, error) {
+	data := make([]byte, len(blocks)*BlockSize)
+	for i := range blocks {
+		blocks[i].put(data[i*BlockSize:])
+	}
+	return data, nil
+}
+
+// decodeBlocks decodes raw data into blocks, the data has to be a multiple of the block size.
+func decodeBlocks(data []byte) ([]Block, error) {
+	if len(data) == 0 || len(data)%BlockSize != 0 || len(data) > NumBlocks*BlockSize {
+		return nil, ErrInvalidSaveSize
+	}
+
+	blocks := make([]Block, len(data)/BlockSize)
+	for i := range blocks {
+		blocks[i].load(data[i*BlockSize:])
+	}
+	return blocks, nil
+}
+
+// extractSave takes the file stored at blockIndex off the card and returns it with its block chain.
+func (mc *MemoryCard) extractSave(blockIndex int) (*Save, []int, error) {
+	if blockIndex < 0 || blockIndex >= NumBlocks {
+		return nil, nil, ErrInvalidBlockIndex
+	}
+
+	if !mc.DirectoryFrames[blockIndex].BlockAllocationState.IsInUse() {
+		return nil, nil, ErrSourceBlockNotInUse
+	}
+
+	chain, err := mc.FileChain(blockIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	save := &Save{DirectoryFrame: mc.DirectoryFrames[chain[0]]}
+	for _, idx := range chain {
+		save.Blocks = append(save.Blocks, mc.readBlock(idx))
+	}
+
+	return save, chain, nil
+}
+
+// ExtractSave returns the file stored at blockIndex, blockIndex may point to any block of the file.
+func (mc *MemoryCard) ExtractSave(blockIndex int) (*Save, error) {
+	save, _, err := mc.extractSave(blockIndex)
+	return save, err
+}
+
+// InsertSave writes the save to free blocks of the card, see FindFreeBlocks, and returns the blocks it was written to.
+// The directory frames of the blocks are rebuilt: allocation states, NextBlock links, file size,
+// title frame block number and checksums, comments of the blocks are cleared.
+// If the card does not have enough free blocks, it is not modified.
+func (mc *MemoryCard) InsertSave(save *Save) ([]int, error) {
+	if save == nil || len(save.Blocks) == 0 || len(save.Blocks) > NumBlocks {
+		return nil, ErrInvalidSave
+	}
+
+	// Reserve all blocks up front, so a full card is rejected before anything is written
+	chain, found := mc.FindFreeBlocks(len(save.Blocks))
+	if !found {
+		if _, hasFree := mc.FindFreeBlock(); hasFree {
+			return nil, ErrNotEnoughFreeBlocks
+		}
+		return nil, ErrNoFreeBlockAvailable
+	}
+
+	for pos, idx := range chain {
+		block := save.Blocks[pos]
+
+		// Only the first block of a file carries the file name and size
+		if pos == 0 {
+			// The title frame block number has to match the new block index.
+			// Only the first block has a title frame, the following blocks hold raw save data.
+			block.TitleFrame.BlockNumber = byte(idx + 1)
+			mc.DirectoryFrames[idx] = save.DirectoryFrame
+			mc.DirectoryFrames[idx].FileSize = uint32(len(chain) * BlockSize)
+		} else {
+			mc.DirectoryFrames[idx] = newFreeDirectoryFrame()
+			mc.DirectoryFrames[idx].FileSize = 0
+		}
+
+		mc.writeBlock(idx, block)
+
+		// The new file must not show the comment of a file that was stored in the block before
+		if mc.meta != nil {
+			mc.meta.comments[idx] = ""
+		}
+	}
+
+	// Link the blocks and recalculate the directory frame checksums
+	mc.linkChain(chain,
+		BlockAllocationStateInUseFirstOnlyBlock,
+		BlockAllocationStateInUseMiddleBlock,
+		BlockAllocationStateInUseLastBlock,
+	)
+
+	return chain, nil
+}
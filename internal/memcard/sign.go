@@ -0,0 +1,68 @@
+package memcard
+
+import (
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha1"
+)
+
+// PS1 Classics on PSP, PS Vita and PS3 sign memory card images (.VMP) and exported saves (.PSV)
+// with an HMAC-SHA1 over the whole file. The HMAC key is derived from a 20 byte seed stored in the
+// file header using AES-128 with a public key and IV:
+//
+//	key[0x00:0x10] = AES-ECB-Decrypt(seed[0x00:0x10]) XOR iv
+//	key[0x10:0x14] = AES-ECB-Encrypt(seed[0x00:0x10])[0x00:0x04] XOR seed[0x10:0x14]
+//
+// The signature field itself is zeroed while the HMAC is calculated.
+const (
+	signatureSeedSize = 0x14
+	signatureSize     = sha1.Size
+)
+
+var (
+	ps1SignatureKey = []byte{0xAB, 0x5A, 0xBC, 0x9F, 0xC1, 0xF4, 0x9D, 0xE6, 0xA0, 0x51, 0xDB, 0xAE, 0xFA, 0x51, 0x88, 0x59}
+	ps1SignatureIV  = []byte{0xB3, 0x0F, 0xFE, 0xED, 0xB7, 0xDC, 0x5E, 0xB7, 0x13, 0x3D, 0xA6, 0x0D, 0x1B, 0x6B, 0x2C, 0xDC}
+)
+
+// ps1SignatureHMACKey derives the HMAC key from the seed stored in a file header.
+func ps1SignatureHMACKey(seed []byte) []byte {
+	cipher, err := aes.NewCipher(ps1SignatureKey)
+	if err != nil {
+		// The key has a fixed, valid size
+		panic(err)
+	}
+
+	decrypted := make([]byte, aes.BlockSize)
+	encrypted := make([]byte, aes.BlockSize)
+	cipher.Decrypt(decrypted, seed[:aes.BlockSize])
+	cipher.Encrypt(encrypted, seed[:aes.BlockSize])
+
+	key := make([]byte, signatureSeedSize)
+	for i := 0; i < aes.BlockSize; i++ {
+		key[i] = decrypted[i] ^ ps1SignatureIV[i]
+	}
+	for i := aes.BlockSize; i < signatureSeedSize; i++ {
+		key[i] = encrypted[i-aes.BlockSize] ^ seed[i]
+	}
+	return key
+}
+
+// ps1Signature calculates the signature of a file, with the seed and the signature stored
+// at the given offsets of the file.
+func ps1Signature(data []byte, seedOffset, signatureOffset int) []byte {
+	mac := hmac.New(sha1.New, ps1SignatureHMACKey(data[seedOffset:seedOffset+signatureSeedSize]))
+	mac.Write(data[:signatureOffset])
+	mac.Write(make([]byte, signatureSize))
+	mac.Write(data[signatureOffset+signatureSize:])
+	return mac.Sum(nil)
+}
+
+// signPS1File stores the signature of the file in the file.
+func signPS1File(data []byte, seedOffset, signatureOffset int) {
+	copy(data[signatureOffset:], ps1Signature(data, seedOffset, signatureOffset))
+}
+
+// verifyPS1File reports whether the signature stored in the file is valid.
+func verifyPS1File(data []byte, seedOffset, signatureOffset int) bool {
+	return hmac.Equal(data[signatureOffset:signatureOffset+signatureSize], ps1Signature(data, seedOffset, signatureOffset))
+}
@@ -2,29 +2,63 @@ package memcard
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"strings"
 
 	"golang.org/x/text/encoding/japanese"
 	"golang.org/x/text/transform"
 )
 
+// MaxShiftJISLength is the size of a title in bytes. Most characters take 2 bytes in Shift-JIS,
+// so titles have at most 32 full-width characters.
+const MaxShiftJISLength = 64
+
+var (
+	ErrTitleTooLong   = errors.New("title is too long, expected at most 64 bytes in Shift-JIS")
+	ErrTitleCharacter = errors.New("title contains a character that can not be encoded in Shift-JIS")
+)
+
 type ShiftJISString struct {
-	Data [64]byte
+	Data [MaxShiftJISLength]byte
 }
 
+// NewShiftJISString encodes the string in Shift-JIS, padded with null bytes.
 func NewShiftJISString(str string) (ShiftJISString, error) {
 	encoder := japanese.ShiftJIS.NewEncoder()
 	encodedStr, _, err := transform.String(encoder, str)
 	if err != nil {
-		return ShiftJISString{}, err
+		return ShiftJISString{}, fmt.Errorf("%w: %q", ErrTitleCharacter, str)
+	}
+	if len(encodedStr) > MaxShiftJISLength {
+		return ShiftJISString{}, fmt.Errorf("%w: %q takes %d bytes", ErrTitleTooLong, str, len(encodedStr))
 	}
 
-	var data [64]byte
+	var data [MaxShiftJISLength]byte
 	copy(data[:], encodedStr)
 
 	return ShiftJISString{Data: data}, nil
 }
 
+// ToFullWidth converts printable ASCII characters to their full-width forms, which the BIOS
+// uses for titles. Spaces become ideographic spaces, other characters are kept.
+// The full-width quotes are IBM extensions the BIOS can not draw, quotes become the JIS X 0208 quotation marks.
+func ToFullWidth(str string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '\u3000'
+		case r == '"':
+			return '\u201D'
+		case r == '\'':
+			return '\u2019'
+		case r > ' ' && r <= '~':
+			return r - '!' + '\uFF01'
+		}
+		return r
+	}, str)
+}
+
 func (s *ShiftJISString) String() string {
 
 	decoder := japanese.ShiftJIS.NewDecoder()
@@ -34,10 +68,8 @@ func (s *ShiftJISString) String() string {
 		nullByteIndex = len(s.Data)
 	}
 
-	str, _, err := transform.String(decoder, string(s.Data[:nullByteIndex]))
-	if err != nil {
-		return ""
-	}
+	// The decoder replaces bytes that do not form a character with the Unicode replacement character
+	str, _, _ := transform.String(decoder, string(s.Data[:nullByteIndex]))
 
 	return strings.TrimSpace(str)
 }
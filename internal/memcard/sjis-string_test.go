@@ -1,6 +1,10 @@
 package memcard
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestShiftJISString_String(t *testing.T) {
 	shiftJISString := ShiftJISString{
@@ -29,3 +33,56 @@ func TestNewShiftJISString(t *testing.T) {
 		t.Errorf("Expected: %s, but got: %s", original, result)
 	}
 }
+
+func TestNewShiftJISString_Errors(t *testing.T) {
+	tests := []struct {
+		str string
+		err error
+	}{
+		{str: strings.Repeat("Ａ", 33), err: ErrTitleTooLong},
+		{str: strings.Repeat("A", 65), err: ErrTitleTooLong},
+		{str: "Save 😀", err: ErrTitleCharacter},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewShiftJISString(tt.str); !errors.Is(err, tt.err) {
+			t.Errorf("Expected %v for %q, but got: %v", tt.err, tt.str, err)
+		}
+	}
+
+	// 32 full-width characters fill the title without padding
+	full, err := NewShiftJISString(strings.Repeat("Ａ", 32))
+	if err != nil || full.Data[63] == 0 {
+		t.Errorf("Expected 64 bytes title, but got: %v (%v)", full.Data, err)
+	}
+}
+
+func TestToFullWidth(t *testing.T) {
+	if result := ToFullWidth("FF8 Save~1 ＯＫ"); result != "ＦＦ８　Ｓａｖｅ～１　ＯＫ" {
+		t.Errorf("Expected full-width title, but got: %s", result)
+	}
+	if result := ToFullWidth(`"Tom's"`); result != "”Ｔｏｍ’ｓ”" {
+		t.Errorf("Expected JIS X 0208 quotation marks, but got: %s", result)
+	}
+
+	// Every printable ASCII character must become a JIS X 0208 symbol or letter (lead byte 0x81 or 0x82)
+	for r := rune(' '); r <= '~'; r++ {
+		encoded, err := NewShiftJISString(ToFullWidth(string(r)))
+		if err != nil {
+			t.Fatalf("Failed to encode %q: %v", r, err)
+		}
+		if lead := encoded.Data[0]; lead != 0x81 && lead != 0x82 || encoded.Data[2] != 0 {
+			t.Errorf("Expected %q to encode in JIS X 0208, but got: % X", r, encoded.Data[:2])
+		}
+	}
+}
+
+func TestShiftJISString_StringReplacement(t *testing.T) {
+	var s ShiftJISString
+	copy(s.Data[:], []byte{0x82, 0x60, 0xFF, 0x82, 0x61, 0x82})
+
+	// String keeps the replacement characters of the decoder for bytes that do not form a character
+	if result := s.String(); result != "Ａ�Ｂ�" {
+		t.Errorf("Expected replacement characters for undecodable bytes, but got: %q", result)
+	}
+}
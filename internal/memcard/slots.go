@@ -0,0 +1,122 @@
+package memcard
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrBlockNotInUse = errors.New("block is not in use")
+
+// ChainPosition is the position of a block in the block chain of its file.
+type ChainPosition int
+
+const (
+	ChainPositionNone   ChainPosition = iota // free block or block that no file links to
+	ChainPositionFirst                       // first or only block of a file
+	ChainPositionMiddle                      // middle block of a file with 3 or more blocks
+	ChainPositionLast                        // last block of a file with 2 or more blocks
+)
+
+func (p ChainPosition) String() string {
+	switch p {
+	case ChainPositionFirst:
+		return "first"
+	case ChainPositionMiddle:
+		return "middle"
+	case ChainPositionLast:
+		return "last"
+	}
+	return "none"
+}
+
+// Slot describes one of the 15 blocks of a memory card. The file fields describe the file the
+// block belongs to, which is a file stored on the card or, for deleted blocks, a recoverable deleted file.
+type Slot struct {
+	Index       int // physical block index (0-14)
+	State       BlockAllocationState
+	Position    ChainPosition
+	FirstBlock  int    // first block of the file, NoBlockIndex if the block belongs to no file
+	FileSize    uint32 // size of the whole file in bytes
+	FileName    FileName
+	Region      string
+	ProductCode string
+	Title       string
+	Recoverable bool // the block belongs to a deleted file that can be restored
+	Remapped    bool // some frames of the file are stored in replacement frames
+}
+
+// InUse reports whether the slot belongs to a file stored on the card.
+func (s *Slot) InUse() bool {
+	return s.State.IsInUse()
+}
+
+// ListSlots returns all 15 blocks of the card in physical order, including free and deleted blocks.
+// Blocks of broken chains keep the position they have up to the break, blocks no intact chain
+// links to have no file.
+func (mc *MemoryCard) ListSlots() []Slot {
+	slots := make([]Slot, NumBlocks)
+	for i := range slots {
+		slots[i] = Slot{
+			Index:      i,
+			State:      mc.DirectoryFrames[i].BlockAllocationState,
+			FirstBlock: NoBlockIndex,
+		}
+	}
+
+	for first := range NumBlocks {
+		if mc.DirectoryFrames[first].BlockAllocationState != BlockAllocationStateInUseFirstOnlyBlock {
+			continue
+		}
+
+		chain, _ := mc.walkChain(first)
+		mc.fillSlots(slots, chain, false)
+	}
+
+	for _, deleted := range mc.ListDeletedFiles() {
+		mc.fillSlots(slots, deleted.Blocks, true)
+	}
+
+	return slots
+}
+
+// saveFirstBlock returns the first block of the save the block belongs to, which holds its title and icon.
+func (mc *MemoryCard) saveFirstBlock(blockIndex int) (int, error) {
+	if blockIndex < 0 || blockIndex >= NumBlocks {
+		return NoBlockIndex, ErrInvalidBlockIndex
+	}
+
+	slot := mc.ListSlots()[blockIndex]
+	if !slot.InUse() || slot.FirstBlock == NoBlockIndex {
+		return NoBlockIndex, fmt.Errorf("%w: block %d", ErrBlockNotInUse, blockIndex)
+	}
+	return slot.FirstBlock, nil
+}
+
+// fillSlots sets the file fields of the slots of a chain. Slots that already belong to a file are skipped,
+// so a cross-linked block stays with the file that claimed it first.
+func (mc *MemoryCard) fillSlots(slots []Slot, chain []int, recoverable bool) {
+	first := chain[0]
+	df := &mc.DirectoryFrames[first]
+	block := mc.readBlock(first)
+	remapped := false
+	for _, idx := range chain {
+		remapped = remapped || len(mc.RemappedFrames(idx)) > 0
+	}
+
+	for pos, idx := range chain {
+		slot := &slots[idx]
+		if slot.FirstBlock != NoBlockIndex {
+			continue
+		}
+
+		slot.Position = chainState(pos, len(chain), ChainPositionFirst, ChainPositionMiddle, ChainPositionLast)
+		slot.FirstBlock = first
+		slot.FileSize = df.FileSize
+		slot.FileName = df.FileName
+		slot.Region = df.FileName.Region()
+		slot.ProductCode = df.FileName.GameCode()
+		slot.Title = block.TitleFrame.Title.String()
+		slot.Recoverable = recoverable
+		slot.Remapped = remapped
+	}
+}
@@ -0,0 +1,48 @@
+package memcard
+
+import (
+	"testing"
+)
+
+func TestListSlots(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 4, 9, 2)
+	writeTestFile(t, card, "BESLES-01234GAME", 6)
+	if err := card.DeleteBlockFrom(6); err != nil {
+		t.Fatalf("Error deleting file: %v", err)
+	}
+
+	slots := card.ListSlots()
+	if len(slots) != NumBlocks {
+		t.Fatalf("Expected %d slots, but got: %d", NumBlocks, len(slots))
+	}
+
+	tests := []struct {
+		index       int
+		position    ChainPosition
+		firstBlock  int
+		recoverable bool
+	}{
+		{index: 0, position: ChainPositionNone, firstBlock: NoBlockIndex},
+		{index: 4, position: ChainPositionFirst, firstBlock: 4},
+		{index: 9, position: ChainPositionMiddle, firstBlock: 4},
+		{index: 2, position: ChainPositionLast, firstBlock: 4},
+		{index: 6, position: ChainPositionFirst, firstBlock: 6, recoverable: true},
+	}
+
+	for _, tt := range tests {
+		slot := slots[tt.index]
+		if slot.Index != tt.index || slot.Position != tt.position || slot.FirstBlock != tt.firstBlock || slot.Recoverable != tt.recoverable {
+			t.Errorf("Expected slot %d to be %s of block %d (recoverable %t), but got: %s of block %d (recoverable %t)",
+				tt.index, tt.position, tt.firstBlock, tt.recoverable, slot.Position, slot.FirstBlock, slot.Recoverable)
+		}
+	}
+
+	middle := slots[9]
+	if !middle.InUse() || middle.FileSize != 3*BlockSize || middle.Region != "America" || middle.ProductCode != "SLUS-00892" {
+		t.Errorf("Expected middle slot to describe its file, but got: %+v", middle)
+	}
+	if slots[6].InUse() || slots[6].Region != "Europe" {
+		t.Errorf("Expected deleted slot to describe its deleted file, but got: %+v", slots[6])
+	}
+}
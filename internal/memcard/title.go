@@ -0,0 +1,23 @@
+package memcard
+
+// RenameSave sets the title of the save the block belongs to. With fullWidth, ASCII characters
+// are converted to full-width characters first, like the titles the BIOS shows.
+func (mc *MemoryCard) RenameSave(blockIndex int, title string, fullWidth bool) error {
+	first, err := mc.saveFirstBlock(blockIndex)
+	if err != nil {
+		return err
+	}
+
+	if fullWidth {
+		title = ToFullWidth(title)
+	}
+	encoded, err := NewShiftJISString(title)
+	if err != nil {
+		return err
+	}
+
+	block := mc.readBlock(first)
+	block.TitleFrame.Title = encoded
+	mc.writeBlock(first, block)
+	return nil
+}
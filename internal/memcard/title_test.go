@@ -0,0 +1,50 @@
+package memcard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRenameSave(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	writeTestFile(t, card, "BASLUS-00892FF7", 2, 6)
+	card.Blocks[2].TitleFrame.Title, _ = NewShiftJISString("ＯＬＤ　ＴＩＴＬＥ　ＷＩＴＨ　ＭＯＲＥ　ＢＹＴＥＳ")
+
+	// Renaming through a linked block renames the save
+	if err := card.RenameSave(6, "Disc 1 Save", true); err != nil {
+		t.Fatalf("Error renaming save: %v", err)
+	}
+
+	title := card.Blocks[2].TitleFrame.Title
+	if title.String() != "Ｄｉｓｃ　１　Ｓａｖｅ" {
+		t.Errorf("Expected full-width title, but got: %s", title.String())
+	}
+	for i, b := range title.Data[22:] {
+		if b != 0 {
+			t.Fatalf("Expected null padding, but byte %d is %#x", 22+i, b)
+		}
+	}
+
+	if err := card.RenameSave(2, "Plain", false); err != nil || card.Blocks[2].TitleFrame.Title.String() != "Plain" {
+		t.Errorf("Expected ASCII title, but got: %s (%v)", card.Blocks[2].TitleFrame.Title.String(), err)
+	}
+
+	tests := []struct {
+		index int
+		title string
+		err   error
+	}{
+		{index: 0, title: "Free", err: ErrBlockNotInUse},
+		{index: NumBlocks, title: "Invalid", err: ErrInvalidBlockIndex},
+		{index: 2, title: "A title that is much too long for thirty two characters", err: ErrTitleTooLong},
+	}
+
+	for _, tt := range tests {
+		if err := card.RenameSave(tt.index, tt.title, true); !errors.Is(err, tt.err) {
+			t.Errorf("Expected %v for block %d, but got: %v", tt.err, tt.index, err)
+		}
+	}
+	if card.Blocks[2].TitleFrame.Title.String() != "Plain" {
+		t.Errorf("Expected failed rename to keep the title, but got: %s", card.Blocks[2].TitleFrame.Title.String())
+	}
+}
@@ -0,0 +1,223 @@
+package memcard
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Frame indices of the header block (block 0) of a memory card.
+const (
+	HeaderFrameIndex              = 0
+	FirstDirectoryFrameIndex      = 1
+	FirstBrokenSelectorFrameIndex = 16
+	WriteTestFrameIndex           = 63
+	NoFrameIndex                  = -1
+	NoBlockIndex                  = -1
+)
+
+// Severity classifies how serious a validation issue is.
+type Severity int
+
+const (
+	SeverityWarning Severity = iota // the card works, but differs from what the BIOS writes
+	SeverityError                   // the card or a file on it is damaged
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// IssueCode identifies the kind of a validation issue.
+type IssueCode string
+
+const (
+	IssueHeaderMagic            IssueCode = "header-magic"
+	IssueHeaderChecksum         IssueCode = "header-checksum"
+	IssueDirectoryChecksum      IssueCode = "directory-checksum"
+	IssueBrokenSelectorChecksum IssueCode = "broken-selector-checksum"
+	IssueWriteTestFrame         IssueCode = "write-test-frame"
+	IssueDanglingNextBlock      IssueCode = "dangling-next-block"
+	IssueChainLoop              IssueCode = "chain-loop"
+	IssueChainState             IssueCode = "chain-state"
+	IssueCrossLinkedBlock       IssueCode = "cross-linked-block"
+	IssueOrphanBlock            IssueCode = "orphan-block"
+	IssueFileSizeMismatch       IssueCode = "file-size-mismatch"
+	IssueTitleFrameMagic        IssueCode = "title-frame-magic"
+)
+
+// Issue is a single finding of a memory card validation.
+// Frame is the frame index within the header block (0-63) and Block the block index (0-14),
+// either is NoFrameIndex or NoBlockIndex when the issue is not bound to it.
+type Issue struct {
+	Code     IssueCode
+	Severity Severity
+	Frame    int
+	Block    int
+	Message  string
+}
+
+func (i Issue) String() string {
+	switch {
+	case i.Block != NoBlockIndex:
+		return fmt.Sprintf("%s: block %d: %s", i.Severity, i.Block, i.Message)
+	case i.Frame != NoFrameIndex:
+		return fmt.Sprintf("%s: frame %d: %s", i.Severity, i.Frame, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+}
+
+// ValidationReport lists all issues found on a memory card.
+type ValidationReport struct {
+	Issues []Issue
+}
+
+// Valid reports whether the card has no issues with error severity.
+func (r *ValidationReport) Valid() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// Clean reports whether the card has no issues at all.
+func (r *ValidationReport) Clean() bool {
+	return len(r.Issues) == 0
+}
+
+func (r *ValidationReport) add(code IssueCode, severity Severity, frame, block int, format string, args ...any) {
+	r.Issues = append(r.Issues, Issue{
+		Code:     code,
+		Severity: severity,
+		Frame:    frame,
+		Block:    block,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks the integrity of the memory card.
+// It verifies the header, directory frame and broken selector checksums, the write test frame
+// and the block chains of all files stored on the card.
+func (mc *MemoryCard) Validate() *ValidationReport {
+	report := &ValidationReport{}
+
+	mc.validateHeader(report)
+	mc.validateDirectoryFrames(report)
+	mc.validateBrokenSelectors(report)
+	mc.validateChains(report)
+
+	return report
+}
+
+func (mc *MemoryCard) validateHeader(report *ValidationReport) {
+	if mc.Header.MagicBytes != HeaderFrameMagic {
+		report.add(IssueHeaderMagic, SeverityError, HeaderFrameIndex, NoBlockIndex,
+			"header magic is %q, expected \"MC\"", mc.Header.MagicBytes[:])
+	}
+
+	if expected := calculateHeaderChecksum(&mc.Header); mc.Header.Checksum != expected {
+		report.add(IssueHeaderChecksum, SeverityWarning, HeaderFrameIndex, NoBlockIndex,
+			"header checksum is 0x%02X, expected 0x%02X", mc.Header.Checksum, expected)
+	}
+
+	// The write test frame is usually a copy of the header frame
+	if mc.WriteTestFrame != headerFrameBytes(&mc.Header) {
+		report.add(IssueWriteTestFrame, SeverityWarning, WriteTestFrameIndex, NoBlockIndex,
+			"write test frame does not match the header frame")
+	}
+}
+
+func (mc *MemoryCard) validateDirectoryFrames(report *ValidationReport) {
+	for i := range NumBlocks {
+		df := &mc.DirectoryFrames[i]
+		if expected := calculateDirectoryFrameChecksum(df); df.Checksum != expected {
+			report.add(IssueDirectoryChecksum, SeverityWarning, FirstDirectoryFrameIndex+i, i,
+				"directory frame checksum is 0x%02X, expected 0x%02X", df.Checksum, expected)
+		}
+	}
+}
+
+func (mc *MemoryCard) validateBrokenSelectors(report *ValidationReport) {
+	for i := range mc.BrokenSelectors {
+		selector := &mc.BrokenSelectors[i]
+		if expected := calculateBrokenSelectorChecksum(selector); selector.Checksum != expected {
+			report.add(IssueBrokenSelectorChecksum, SeverityWarning, FirstBrokenSelectorFrameIndex+i, NoBlockIndex,
+				"broken selector checksum is 0x%02X, expected 0x%02X", selector.Checksum, expected)
+		}
+	}
+}
+
+func (mc *MemoryCard) validateChains(report *ValidationReport) {
+	owner := [NumBlocks]int{}
+	for i := range owner {
+		owner[i] = NoBlockIndex
+	}
+
+	for first := range NumBlocks {
+		df := &mc.DirectoryFrames[first]
+		if df.BlockAllocationState != BlockAllocationStateInUseFirstOnlyBlock {
+			continue
+		}
+
+		chain, err := mc.walkChain(first)
+		switch {
+		case errors.Is(err, ErrBlockChainLoop):
+			last := chain[len(chain)-1]
+			report.add(IssueChainLoop, SeverityError, FirstDirectoryFrameIndex+last, last,
+				"next block %d loops back into the chain of the file starting at block %d", mc.DirectoryFrames[last].NextBlock, first)
+		case errors.Is(err, ErrDanglingLink):
+			last := chain[len(chain)-1]
+			report.add(IssueDanglingNextBlock, SeverityError, FirstDirectoryFrameIndex+last, last,
+				"next block %d does not exist", mc.DirectoryFrames[last].NextBlock)
+		}
+
+		for pos, idx := range chain {
+			if owner[idx] != NoBlockIndex {
+				report.add(IssueCrossLinkedBlock, SeverityError, FirstDirectoryFrameIndex+idx, idx,
+					"block is used by the files starting at block %d and block %d", owner[idx], first)
+				continue
+			}
+			owner[idx] = first
+
+			if pos == 0 {
+				continue
+			}
+
+			// A chain that was cut short by a loop or a dangling link has no valid last block
+			expected := chainState(pos, len(chain), BlockAllocationStateInUseFirstOnlyBlock, BlockAllocationStateInUseMiddleBlock, BlockAllocationStateInUseLastBlock)
+			state := mc.DirectoryFrames[idx].BlockAllocationState
+			if err != nil && pos == len(chain)-1 && state == BlockAllocationStateInUseMiddleBlock {
+				continue
+			}
+			if state != expected {
+				report.add(IssueChainState, SeverityError, FirstDirectoryFrameIndex+idx, idx,
+					"block has state 0x%02X, expected 0x%02X as block %d of the file starting at block %d", uint32(state), uint32(expected), pos+1, first)
+			}
+		}
+
+		if expected := uint32(len(chain) * BlockSize); df.FileSize != expected {
+			report.add(IssueFileSizeMismatch, SeverityError, FirstDirectoryFrameIndex+first, first,
+				"file size is %d bytes, but the chain has %d blocks (%d bytes)", df.FileSize, len(chain), expected)
+		}
+
+		if block := mc.readBlock(first); !block.TitleFrame.HasMagic() {
+			report.add(IssueTitleFrameMagic, SeverityError, NoFrameIndex, first,
+				"title frame does not start with \"SC\" and a valid icon display flag")
+		}
+	}
+
+	for i := range NumBlocks {
+		state := mc.DirectoryFrames[i].BlockAllocationState
+		if (state == BlockAllocationStateInUseMiddleBlock || state == BlockAllocationStateInUseLastBlock) && owner[i] == NoBlockIndex {
+			report.add(IssueOrphanBlock, SeverityError, FirstDirectoryFrameIndex+i, i,
+				"block has state 0x%02X, but no file links to it", uint32(state))
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package memcard
+
+import (
+	"testing"
+)
+
+func TestValidate_FormattedCard(t *testing.T) {
+	report := NewFormattedMemoryCard().Validate()
+
+	if !report.Clean() {
+		t.Errorf("Expected no issues on a formatted card, but got: %v", report.Issues)
+	}
+}
+
+func TestValidate_DummyCard(t *testing.T) {
+	card, err := Open("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error opening card: %v", err)
+	}
+
+	if report := card.Validate(); !report.Clean() {
+		t.Errorf("Expected no issues, but got: %v", report.Issues)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		damage   func(card *MemoryCard)
+		code     IssueCode
+		severity Severity
+		block    int
+	}{
+		{
+			name: "header magic",
+			damage: func(card *MemoryCard) {
+				card.Header.MagicBytes = [2]byte{'X', 'Y'}
+				card.Header.Checksum = calculateHeaderChecksum(&card.Header)
+			},
+			code:     IssueHeaderMagic,
+			severity: SeverityError,
+			block:    NoBlockIndex,
+		},
+		{
+			name:     "header checksum",
+			damage:   func(card *MemoryCard) { card.Header.Checksum ^= 0xFF },
+			code:     IssueHeaderChecksum,
+			severity: SeverityWarning,
+			block:    NoBlockIndex,
+		},
+		{
+			name:     "directory checksum",
+			damage:   func(card *MemoryCard) { card.DirectoryFrames[5].Checksum ^= 0xFF },
+			code:     IssueDirectoryChecksum,
+			severity: SeverityWarning,
+			block:    5,
+		},
+		{
+			name:     "broken selector checksum",
+			damage:   func(card *MemoryCard) { card.BrokenSelectors[3].Checksum ^= 0xFF },
+			code:     IssueBrokenSelectorChecksum,
+			severity: SeverityWarning,
+			block:    NoBlockIndex,
+		},
+		{
+			name:     "write test frame",
+			damage:   func(card *MemoryCard) { card.WriteTestFrame[10] = 0x42 },
+			code:     IssueWriteTestFrame,
+			severity: SeverityWarning,
+			block:    NoBlockIndex,
+		},
+		{
+			name: "dangling next block",
+			damage: func(card *MemoryCard) {
+				card.DirectoryFrames[1].NextBlock = 0x20
+			},
+			code:     IssueDanglingNextBlock,
+			severity: SeverityError,
+			block:    1,
+		},
+		{
+			name: "chain loop",
+			damage: func(card *MemoryCard) {
+				card.DirectoryFrames[2].NextBlock = 1
+			},
+			code:     IssueChainLoop,
+			severity: SeverityError,
+			block:    2,
+		},
+		{
+			name: "orphan last block",
+			damage: func(card *MemoryCard) {
+				card.DirectoryFrames[1].NextBlock = NoNextBlock
+			},
+			code:     IssueOrphanBlock,
+			severity: SeverityError,
+			block:    2,
+		},
+		{
+			name: "file size mismatch",
+			damage: func(card *MemoryCard) {
+				card.DirectoryFrames[0].FileSize = BlockSize
+			},
+			code:     IssueFileSizeMismatch,
+			severity: SeverityError,
+			block:    0,
+		},
+		{
+			name: "title frame magic",
+			damage: func(card *MemoryCard) {
+				card.Blocks[0].TitleFrame.ID = [2]byte{0, 0}
+			},
+			code:     IssueTitleFrameMagic,
+			severity: SeverityError,
+			block:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := NewFormattedMemoryCard()
+			writeTestFile(t, card, "BASLUS-00892FF7", 0, 1, 2)
+			tt.damage(card)
+
+			report := card.Validate()
+			for _, issue := range report.Issues {
+				if issue.Code == tt.code {
+					if issue.Severity != tt.severity || issue.Block != tt.block {
+						t.Errorf("Expected %s on block %d, but got: %v", tt.severity, tt.block, issue)
+					}
+					return
+				}
+			}
+			t.Errorf("Expected issue %s, but got: %v", tt.code, report.Issues)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package memcard
+
+import (
+	"bytes"
+)
+
+// Connectix Virtual Game Station images (.mem, .vgs) start with a 64 byte header:
+//
+//	0x00-0x03  "VgsM"
+//	0x04       0x01
+//	0x08       0x01
+//	0x0C       0x01
+//	0x11       0x02
+//
+// Other tools put a small header of unknown layout in front of the raw memory card.
+// Those images are recognized by the "MC" magic of the memory card behind the header,
+// the header is kept as is and written back unchanged.
+const (
+	VGSHeaderSize       = 64
+	maxPrefixHeaderSize = 512
+)
+
+var vgsMagic = []byte("VgsM")
+
+// isVGSImage reports whether the data is a Connectix Virtual Game Station image.
+func isVGSImage(data []byte) bool {
+	return len(data) == VGSHeaderSize+MemoryCardTotalSize && bytes.HasPrefix(data, vgsMagic)
+}
+
+// prefixHeaderSize returns the size of the unknown header in front of the memory card,
+// or -1 if the data does not look like a memory card with a small header.
+func prefixHeaderSize(data []byte) int {
+	headerSize := len(data) - MemoryCardTotalSize
+	if headerSize <= 0 || headerSize > maxPrefixHeaderSize {
+		return -1
+	}
+
+	if !bytes.HasPrefix(data[headerSize:], HeaderFrameMagic[:]) {
+		return -1
+	}
+	return headerSize
+}
+
+// prefixedCardCodec reads and writes memory cards behind a header.
+// The header a card was loaded with is written back unchanged, cards loaded from
+// another format get a new header if the format defines one.
+type prefixedCardCodec struct {
+	format     CardFormat
+	extensions []string
+	headerSize func(data []byte) int // size of the header in front of the card, -1 if the data is not of the format
+	newHeader  func() []byte         // nil if the format has no default header
+	sign       func(data []byte)     // nil if the format is not signed
+}
+
+var (
+	vgsCodec = &prefixedCardCodec{
+		format:     CardFormatVGS,
+		extensions: []string{".mem", ".vgs"},
+		headerSize: func(data []byte) int {
+			if !isVGSImage(data) {
+				return -1
+			}
+			return VGSHeaderSize
+		},
+		newHeader: newVGSHeader,
+	}
+
+	prefixedCodec = &prefixedCardCodec{
+		format:     CardFormatPrefixed,
+		headerSize: prefixHeaderSize,
+	}
+)
+
+func (c *prefixedCardCodec) Format() CardFormat {
+	return c.format
+}
+
+func (c *prefixedCardCodec) Extensions() []string {
+	return c.extensions
+}
+
+func (c *prefixedCardCodec) Detect(data []byte) bool {
+	return c.headerSize(data) != -1
+}
+
+func (c *prefixedCardCodec) Decode(data []byte) (*MemoryCard, error) {
+	headerSize := c.headerSize(data)
+	if headerSize == -1 {
+		return nil, ErrUnknownCardFormat
+	}
+	return readPrefixedImage(data, headerSize)
+}
+
+func (c *prefixedCardCodec) Encode(card *MemoryCard) ([]byte, error) {
+	raw, err := card.encodeRawImage()
+	if err != nil {
+		return nil, err
+	}
+
+	var header []byte
+	if card.Format() == c.format {
+		header = card.metadata().header
+	}
+	if header == nil && c.newHeader != nil {
+		header = c.newHeader()
+	}
+	if header == nil {
+		return nil, ErrMissingImageHeader
+	}
+
+	data := append(bytes.Clone(header), raw...)
+	if c.sign != nil {
+		c.sign(data)
+	}
+	return data, nil
+}
+
+// readPrefixedImage decodes a memory card behind a header and keeps the header.
+func readPrefixedImage(data []byte, headerSize int) (*MemoryCard, error) {
+	card, err := readRawImage(data[headerSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	card.metadata().header = bytes.Clone(data[:headerSize])
+	return card, nil
+}
+
+// newVGSHeader creates the header written by Connectix Virtual Game Station.
+func newVGSHeader() []byte {
+	header := make([]byte, VGSHeaderSize)
+	copy(header, vgsMagic)
+	header[0x04] = 0x01
+	header[0x08] = 0x01
+	header[0x0C] = 0x01
+	header[0x11] = 0x02
+	return header
+}
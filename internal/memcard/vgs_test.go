@@ -0,0 +1,78 @@
+package memcard
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrefixedImage_RoundTrip(t *testing.T) {
+	raw, err := os.ReadFile("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error reading card: %v", err)
+	}
+
+	vgsHeader := newVGSHeader()
+	vgsHeader[0x20] = 0x42 // tool specific data must be kept
+
+	tests := []struct {
+		name   string
+		header []byte
+		format CardFormat
+	}{
+		{name: "vgs", header: vgsHeader, format: CardFormatVGS},
+		{name: "unknown header", header: []byte("DUMPv1\x00\x00\x10\x00"), format: CardFormatPrefixed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			image := append(bytes.Clone(tt.header), raw...)
+			path := filepath.Join(dir, "card.mem")
+			if err := os.WriteFile(path, image, 0600); err != nil {
+				t.Fatalf("Error writing image: %v", err)
+			}
+
+			card, err := Open(path)
+			if err != nil {
+				t.Fatalf("Error opening image: %v", err)
+			}
+			if card.Format() != tt.format {
+				t.Errorf("Expected format %s, but got: %s", tt.format, card.Format())
+			}
+
+			written := filepath.Join(dir, "written.mem")
+			if err := card.Write(written); err != nil {
+				t.Fatalf("Error writing card: %v", err)
+			}
+
+			data, err := os.ReadFile(written)
+			if err != nil {
+				t.Fatalf("Error reading written card: %v", err)
+			}
+			if !bytes.Equal(data, image) {
+				t.Errorf("Expected written image to match the loaded image")
+			}
+		})
+	}
+}
+
+func TestPrefixedImage_NewVGSHeader(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	if err := card.SetFormat(CardFormatVGS); err != nil {
+		t.Fatalf("Error setting format: %v", err)
+	}
+
+	data, err := card.Codec().Encode(card)
+	if err != nil {
+		t.Fatalf("Error encoding card: %v", err)
+	}
+	if !isVGSImage(data) {
+		t.Errorf("Expected a VGS image")
+	}
+
+	if err := NewFormattedMemoryCard().SetFormat(CardFormatPrefixed); err != ErrMissingImageHeader {
+		t.Errorf("Expected ErrMissingImageHeader, but got: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package memcard
+
+import (
+	"bytes"
+	"errors"
+)
+
+// PSP and PS Vita PS1 Classics store memory cards as SCEVMC0.VMP and SCEVMC1.VMP files.
+// They hold the raw memory card behind a 0x80 byte header with a signature over the whole file,
+// the console refuses cards with a wrong signature:
+//
+//	0x00-0x03  "\x00PMV"
+//	0x04-0x07  header size (0x80)
+//	0x0C-0x1F  signature seed
+//	0x20-0x33  signature (see sign.go)
+const (
+	VMPHeaderSize      = 0x80
+	vmpSeedOffset      = 0x0C
+	vmpSignatureOffset = 0x20
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	vmpMagic = []byte("\x00PMV")
+)
+
+// isVMPImage reports whether the data is a PSP or PS Vita virtual memory card.
+func isVMPImage(data []byte) bool {
+	return len(data) == VMPHeaderSize+MemoryCardTotalSize && bytes.HasPrefix(data, vmpMagic)
+}
+
+// newVMPHeader creates an unsigned VMP header with an all zero signature seed.
+func newVMPHeader() []byte {
+	header := make([]byte, VMPHeaderSize)
+	copy(header, vmpMagic)
+	header[0x04] = VMPHeaderSize
+	return header
+}
+
+// VerifyVMPImage checks the signature of a PSP or PS Vita virtual memory card file.
+func VerifyVMPImage(data []byte) error {
+	if !isVMPImage(data) {
+		return ErrUnknownCardFormat
+	}
+
+	if !verifyPS1File(data, vmpSeedOffset, vmpSignatureOffset) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+var vmpCodec = &prefixedCardCodec{
+	format:     CardFormatVMP,
+	extensions: []string{".vmp"},
+	headerSize: func(data []byte) int {
+		if !isVMPImage(data) {
+			return -1
+		}
+		return VMPHeaderSize
+	},
+	newHeader: newVMPHeader,
+	sign: func(data []byte) {
+		signPS1File(data, vmpSeedOffset, vmpSignatureOffset)
+	},
+}
@@ -0,0 +1,159 @@
+package memcard
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// AES key and IV of the PS1 signatures as published with the PS Vita resigning tools, kept apart from
+// the ones in sign.go so a typo in either is caught.
+var (
+	referenceSignatureKey = []byte("\xAB\x5A\xBC\x9F\xC1\xF4\x9D\xE6\xA0\x51\xDB\xAE\xFA\x51\x88\x59")
+	referenceSignatureIV  = []byte("\xB3\x0F\xFE\xED\xB7\xDC\x5E\xB7\x13\x3D\xA6\x0D\x1B\x6B\x2C\xDC")
+)
+
+// referencePS1Signature signs a file the way the PS Vita resigning tools do,
+// with the salt XORed with the HMAC pads by hand.
+func referencePS1Signature(data []byte, seedOffset, signatureOffset int) []byte {
+	seed := data[seedOffset : seedOffset+0x14]
+	cipher, _ := aes.NewCipher(referenceSignatureKey)
+
+	salt := make([]byte, 0x40)
+	cipher.Decrypt(salt[0x00:0x10], seed[:0x10])
+	cipher.Encrypt(salt[0x10:0x20], seed[:0x10])
+	for i := range 0x10 {
+		salt[i] ^= referenceSignatureIV[i]
+	}
+	work := []byte{seed[0x10], seed[0x11], seed[0x12], seed[0x13], 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	for i := range 0x10 {
+		salt[0x10+i] ^= work[i]
+	}
+	clear(salt[0x14:])
+
+	unsigned := append([]byte{}, data...)
+	clear(unsigned[signatureOffset : signatureOffset+0x14])
+
+	for i := range salt {
+		salt[i] ^= 0x36
+	}
+	inner := sha1.New()
+	inner.Write(salt)
+	inner.Write(unsigned)
+	innerSum := inner.Sum(nil)
+
+	for i := range salt {
+		salt[i] ^= 0x36 ^ 0x5C
+	}
+	outer := sha1.New()
+	outer.Write(salt)
+	outer.Write(innerSum)
+	return outer.Sum(nil)
+}
+
+func TestVMPImage_SignedOnWrite(t *testing.T) {
+	source, err := Open("../../dummy-cards/epsxe000.mcr")
+	if err != nil {
+		t.Fatalf("Error opening card: %v", err)
+	}
+
+	// A VMP file as it comes from the console, with a non zero seed
+	header := newVMPHeader()
+	for i := range 0x14 {
+		header[vmpSeedOffset+i] = byte(0x10 + i)
+	}
+	raw, err := NewFormattedMemoryCard().encodeRawImage()
+	if err != nil {
+		t.Fatalf("Error encoding card: %v", err)
+	}
+	image := append(header, raw...)
+	signPS1File(image, vmpSeedOffset, vmpSignatureOffset)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "SCEVMC0.VMP")
+	if err := os.WriteFile(path, image, 0600); err != nil {
+		t.Fatalf("Error writing image: %v", err)
+	}
+
+	card, err := Open(path)
+	if err != nil {
+		t.Fatalf("Error opening VMP: %v", err)
+	}
+	if card.Format() != CardFormatVMP {
+		t.Fatalf("Expected format %s, but got: %s", CardFormatVMP, card.Format())
+	}
+
+	if err := source.CopyBlockTo(4, card); err != nil {
+		t.Fatalf("Error copying block: %v", err)
+	}
+	if err := card.Write(path); err != nil {
+		t.Fatalf("Error writing VMP: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading VMP: %v", err)
+	}
+	if err := VerifyVMPImage(data); err != nil {
+		t.Fatalf("Expected a valid signature, but got: %v", err)
+	}
+
+	expected := referencePS1Signature(data, vmpSeedOffset, vmpSignatureOffset)
+	if string(data[vmpSignatureOffset:vmpSignatureOffset+0x14]) != string(expected) {
+		t.Errorf("Expected signature %X, but got: %X", expected, data[vmpSignatureOffset:vmpSignatureOffset+0x14])
+	}
+	if string(data[vmpSeedOffset:vmpSeedOffset+0x14]) != string(header[vmpSeedOffset:vmpSeedOffset+0x14]) {
+		t.Errorf("Expected signature seed to be kept")
+	}
+
+	data[VMPHeaderSize+0x2000] ^= 0xFF
+	if err := VerifyVMPImage(data); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature for a modified card, but got: %v", err)
+	}
+}
+
+// consoleSamples returns the files with the extension in dummy-cards. Files signed by a console are the
+// known answers of the signature scheme, they are not part of the repository. Copy e.g. a SCEVMC0.VMP
+// of a PS Vita or PSP or a .PSV exported by a PS3 into dummy-cards to check the signatures against it.
+func consoleSamples(t *testing.T, extension string) [][]byte {
+	t.Helper()
+
+	paths := []string{}
+	for _, pattern := range []string{"*" + strings.ToUpper(extension), "*" + strings.ToLower(extension)} {
+		matches, _ := filepath.Glob(filepath.Join("../../dummy-cards", pattern))
+		for _, match := range matches {
+			if !slices.Contains(paths, match) {
+				paths = append(paths, match)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		t.Skipf("no %s files signed by a console in dummy-cards", extension)
+	}
+
+	samples := [][]byte{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Error reading %s: %v", path, err)
+		}
+		samples = append(samples, data)
+	}
+	return samples
+}
+
+func TestVerifyVMPImage_ConsoleSamples(t *testing.T) {
+	for i, data := range consoleSamples(t, ".VMP") {
+		if err := VerifyVMPImage(data); err != nil {
+			t.Errorf("Expected console sample %d to verify, but got: %v", i, err)
+		}
+		if expected := referencePS1Signature(data, vmpSeedOffset, vmpSignatureOffset); !bytes.Equal(data[vmpSignatureOffset:vmpSignatureOffset+0x14], expected) {
+			t.Errorf("Expected reference signature %X for console sample %d", expected, i)
+		}
+	}
+}
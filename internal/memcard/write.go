@@ -1,23 +1,88 @@
 package memcard
 
 import (
-	"encoding/binary"
+	"bytes"
+	"encoding"
+	"errors"
 	"fmt"
-	"os"
+	"io"
 )
 
+var (
+	_ encoding.BinaryMarshaler   = (*MemoryCard)(nil)
+	_ encoding.BinaryUnmarshaler = (*MemoryCard)(nil)
+	_ io.WriterTo                = (*MemoryCard)(nil)
+)
+
+// Write writes the card in the format it was loaded from. Cards created in memory are written in the
+// format matching the extension of the file, or as raw image for unknown extensions. The format is
+// picked for each write, it does not become the format of the card.
+//
+// The card is written to a temporary file next to the target, which is synced, read back and
+// checked before it replaces the target. A failed write leaves the original file untouched.
 func (mc *MemoryCard) Write(filePath string) error {
-	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	codec := mc.Codec()
+	if codec == nil {
+		codec = rawCardCodec{}
+		if extensionCodec, err := DefaultCodecRegistry().CardCodecForFileName(filePath); err == nil {
+			codec = extensionCodec
+		}
+	}
+
+	data, err := codec.Encode(mc)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to encode memory card: %w", err)
 	}
 
-	defer file.Close()
+	return writeFileAtomic(filePath, data, func(written []byte) error {
+		return mc.verifyWritten(codec, written)
+	})
+}
 
-	err = binary.Write(file, binary.LittleEndian, mc)
+// verifyWritten checks that data read back from a file written with the codec decodes to this card.
+func (mc *MemoryCard) verifyWritten(codec CardCodec, data []byte) error {
+	written, err := codec.Decode(data)
 	if err != nil {
-		return fmt.Errorf("failed to write memory card to file: %w", err)
+		return err
 	}
 
+	expected, err := mc.encodeRawImage()
+	if err != nil {
+		return err
+	}
+	actual, err := written.encodeRawImage()
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(actual, expected) {
+		return errors.New("written memory card differs from the card in memory")
+	}
 	return nil
 }
+
+// WriteTo writes the card to w in the format it was loaded from, cards created in memory are written as raw image.
+func (mc *MemoryCard) WriteTo(w io.Writer) (int64, error) {
+	data, err := mc.MarshalBinary()
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode memory card: %w", err)
+	}
+
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// MarshalBinary encodes the card in the format it was loaded from, cards created in memory are encoded as raw image.
+func (mc *MemoryCard) MarshalBinary() ([]byte, error) {
+	if codec := mc.Codec(); codec != nil {
+		return codec.Encode(mc)
+	}
+	return mc.encodeRawImage()
+}
+
+// encodeRawImage encodes the card as plain 128 KB memory card image.
+func (mc *MemoryCard) encodeRawImage() ([]byte, error) {
+	data := make([]byte, MemoryCardTotalSize)
+	mc.putRawImage(data)
+	return data, nil
+}
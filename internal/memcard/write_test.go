@@ -0,0 +1,118 @@
+package memcard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// failingCardCodec fails to encode every card.
+type failingCardCodec struct{ rawCardCodec }
+
+func (failingCardCodec) Encode(*MemoryCard) ([]byte, error) {
+	return nil, errors.New("encode failed")
+}
+
+func TestWrite_KeepsPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on windows")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "card.mcr")
+	if err := os.WriteFile(path, nil, 0640); err != nil {
+		t.Fatalf("Error creating file: %v", err)
+	}
+	if err := os.Chmod(path, 0640); err != nil {
+		t.Fatalf("Error changing permissions: %v", err)
+	}
+
+	if err := NewFormattedMemoryCard().Write(path); err != nil {
+		t.Fatalf("Error writing card: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Error reading file info: %v", err)
+	}
+	if info.Mode().Perm() != 0640 || info.Size() != MemoryCardTotalSize {
+		t.Errorf("Expected %d bytes with mode 0640, but got: %d bytes with mode %o", MemoryCardTotalSize, info.Size(), info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Error listing directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files to be left, but got: %v", entries)
+	}
+}
+
+func TestWrite_FailureKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "card.mcr")
+	original := []byte("original card")
+	if err := os.WriteFile(path, original, 0600); err != nil {
+		t.Fatalf("Error creating file: %v", err)
+	}
+
+	card := NewFormattedMemoryCard()
+	if err := card.SetCodec(failingCardCodec{}); err != nil {
+		t.Fatalf("Error setting codec: %v", err)
+	}
+	if err := card.Write(path); err == nil {
+		t.Fatalf("Expected write to fail")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading file: %v", err)
+	}
+	if string(data) != string(original) {
+		t.Errorf("Expected original file to be kept, but got: %q", data)
+	}
+}
+
+func TestWriteFileAtomic_VerifyFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "card.mcr")
+
+	err := writeFileAtomic(path, []byte("data"), func([]byte) error {
+		return errors.New("mismatch")
+	})
+	if !errors.Is(err, ErrWriteVerificationFailed) {
+		t.Fatalf("Expected ErrWriteVerificationFailed, but got: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Error listing directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no file to be written, but got: %v", entries)
+	}
+}
+
+func TestClone_SharesNoMemory(t *testing.T) {
+	card := NewFormattedMemoryCard()
+	if err := card.SetComment(0, "original"); err != nil {
+		t.Fatalf("Error setting comment: %v", err)
+	}
+
+	clone := card.Clone()
+	clone.DirectoryFrames[0].BlockAllocationState = BlockAllocationStateInUseFirstOnlyBlock
+	clone.Blocks[0].Data[0][0] = 0xFF
+	if err := clone.SetComment(0, "changed"); err != nil {
+		t.Fatalf("Error setting comment: %v", err)
+	}
+
+	if card.DirectoryFrames[0].BlockAllocationState != BlockAllocationStateFreeFresh ||
+		card.Blocks[0].Data[0][0] != 0 || card.Comment(0) != "original" {
+		t.Errorf("Expected the original card to be unchanged by changes to its clone")
+	}
+	if clone.Comment(0) != "changed" {
+		t.Errorf("Expected comment of the clone to change, but got: %q", clone.Comment(0))
+	}
+}
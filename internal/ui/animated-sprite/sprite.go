@@ -14,50 +14,51 @@ type Animation struct {
 	FrameDelay int64 // in milliseconds
 }
 
-func NewAnimation(frames []image.Image) Animation {
+// defaultFrameDelay is used for animations without a frame delay, e.g. single frames that do not animate.
+const defaultFrameDelay = 100 * time.Millisecond
 
-	frameDelay := int64(100)
-	switch {
-	case len(frames) == 2:
-		frameDelay = 17 * 11 // 186ms per frame
-	case len(frames) == 3:
-		frameDelay = 17 * 16 // ~272ms per frame
+// NewAnimation creates an animation showing each frame for frameDelay, looping when it has more than one frame.
+func NewAnimation(frames []image.Image, frameDelay time.Duration) Animation {
+	if frameDelay <= 0 {
+		frameDelay = defaultFrameDelay
 	}
 
 	return Animation{
 		Frames:     frames,
 		Loop:       len(frames) > 1,
-		FrameDelay: frameDelay,
+		FrameDelay: frameDelay.Milliseconds(),
 	}
 }
 
 type AnimatedSprite struct {
 	Animation    Animation
 	currentFrame int
+	stop         chan struct{}
 	canvas.Image
 }
 
 func NewAnimatedSprite(animation Animation) *AnimatedSprite {
-	sprite := &AnimatedSprite{}
+	sprite := &AnimatedSprite{stop: make(chan struct{})}
 	sprite.currentFrame = 0
 
 	sprite.SetAnimation(animation)
 
 	ticker := time.NewTicker(time.Millisecond * time.Duration(animation.FrameDelay))
 	go func() {
-		for range ticker.C {
-			if sprite.currentFrame < len(sprite.Animation.Frames)-1 {
-				sprite.currentFrame++
-				fyne.Do(func() {
-					sprite.Refresh()
-				})
-			} else if sprite.Animation.Loop {
-				sprite.currentFrame = 0
-				fyne.Do(func() {
-					sprite.Refresh()
-				})
-			} else {
-				ticker.Stop()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sprite.stop:
+				return
+			case <-ticker.C:
+			}
+
+			// The frames are only read and changed on the UI thread, see SetFrames
+			ended := false
+			fyne.DoAndWait(func() {
+				ended = !sprite.nextFrame()
+			})
+			if ended {
 				return
 			}
 		}
@@ -66,12 +67,46 @@ func NewAnimatedSprite(animation Animation) *AnimatedSprite {
 	return sprite
 }
 
+// Stop stops the animation, the sprite keeps showing its current frame.
+// Sprites that are replaced while their animation loops must be stopped.
+func (s *AnimatedSprite) Stop() {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+}
+
 func (s *AnimatedSprite) Refresh() {
 	s.Image.Image = s.Animation.Frames[s.currentFrame]
 	s.Image.FillMode = canvas.ImageFillContain
+	s.Image.ScaleMode = canvas.ImageScalePixels // keep the pixels of the 16x16 icons sharp
 	s.Image.Refresh()
 }
 
+// nextFrame shows the next frame, it returns false when an animation that does not loop has ended.
+func (s *AnimatedSprite) nextFrame() bool {
+	switch {
+	case s.currentFrame < len(s.Animation.Frames)-1:
+		s.currentFrame++
+	case s.Animation.Loop:
+		s.currentFrame = 0
+	default:
+		return false
+	}
+	s.Refresh()
+	return true
+}
+
+// SetFrames replaces the frames and keeps the current frame, so an animation keeps playing while
+// its frames are edited. It must be called on the UI thread.
+func (s *AnimatedSprite) SetFrames(frames []image.Image) {
+	s.Animation.Frames = frames
+	s.currentFrame = min(s.currentFrame, len(frames)-1)
+	s.Refresh()
+}
+
+// SetAnimation replaces the animation and starts it from the first frame. It must be called on the UI thread.
 func (s *AnimatedSprite) SetAnimation(animation Animation) {
 	s.Animation = animation
 	s.currentFrame = 0
@@ -11,9 +11,15 @@ type BlockModelView struct {
 	CardId         memcard.MemoryCardID
 	Selected       binding.Bool
 	Allocated      binding.Bool
+	Remapped       binding.Bool                           // some frames of the save are stored in replacement frames
 	GameTitle      binding.String                         // binding to string
 	Animation      binding.Item[animatedsprite.Animation] // binding to animatedsprite.Animation
 	blockSelection *SelectionViewModel
+
+	// Context menu actions, nil if not available
+	OnExportIcon         func(format memcard.IconExportFormat)
+	OnImportIcon         func()
+	OnExportContactSheet func()
 }
 
 func NewBlockModelView(idx int, cardId memcard.MemoryCardID, blockSelector *SelectionViewModel) *BlockModelView {
@@ -24,6 +30,7 @@ func NewBlockModelView(idx int, cardId memcard.MemoryCardID, blockSelector *Sele
 		Selected:       binding.NewBool(),
 		blockSelection: blockSelector,
 		Allocated:      binding.NewBool(),
+		Remapped:       binding.NewBool(),
 		GameTitle:      binding.NewString(),
 		Animation:      binding.NewItem((func(a, b animatedsprite.Animation) bool { return len(a.Frames) == len(b.Frames) })),
 	}
@@ -1,7 +1,9 @@
 package blocks
 
 import (
+	"image"
 	"image/color"
+	"image/draw"
 	"reflect"
 
 	"com.yv35.memcard/internal/memcard"
@@ -16,6 +18,7 @@ import (
 var (
 	SELECTED_BORDER_COLOR   = color.RGBA{R: 200, G: 100, B: 100, A: 255}
 	UNSELECTED_BORDER_COLOR = color.RGBA{R: 0, G: 0, B: 00, A: 60}
+	REMAPPED_BORDER_COLOR   = color.RGBA{R: 230, G: 160, B: 0, A: 255}
 	FILL_COLOR              = color.RGBA{R: 100, G: 100, B: 200, A: 255}
 	BLOCK_SIZE              = float32(96)
 )
@@ -26,6 +29,7 @@ type blockView struct {
 	container     *fyne.Container
 	block         *canvas.Rectangle
 	iconContainer *fyne.Container
+	sprite        *animatedsprite.AnimatedSprite
 }
 
 func NewBlockView(idx int, cardId memcard.MemoryCardID, model *BlockModelView) *blockView {
@@ -75,15 +79,21 @@ func NewBlockView(idx int, cardId memcard.MemoryCardID, model *BlockModelView) *
 
 func (v *blockView) setupSelectedBinding() {
 	model := v.model
-	model.Selected.AddListener(binding.NewDataListener(func() {
-		selected := model.IsSelected()
-		if selected {
+	updateBorder := binding.NewDataListener(func() {
+		remapped, _ := model.Remapped.Get()
+		switch {
+		case model.IsSelected():
 			v.block.StrokeColor = SELECTED_BORDER_COLOR
-		} else {
+		case remapped:
+			// Saves with remapped frames are outlined, the card has broken sectors
+			v.block.StrokeColor = REMAPPED_BORDER_COLOR
+		default:
 			v.block.StrokeColor = UNSELECTED_BORDER_COLOR
 		}
 		v.block.Refresh()
-	}))
+	})
+	model.Selected.AddListener(updateBorder)
+	model.Remapped.AddListener(updateBorder)
 }
 
 func (v *blockView) setupIconAnimation() {
@@ -95,15 +105,18 @@ func (v *blockView) setupIconAnimation() {
 		if v.iconContainer != nil && reflect.ValueOf(animation).IsZero() {
 			v.container.Remove(v.iconContainer)
 			v.iconContainer = nil
+			v.sprite.Stop()
 		}
 
 		if !reflect.ValueOf(animation).IsZero() {
-			image := animatedsprite.NewAnimatedSprite(animation)
+			sprite := animatedsprite.NewAnimatedSprite(composeOverBackground(animation, FILL_COLOR))
 			if v.iconContainer != nil {
 				v.container.Remove(v.iconContainer)
+				v.sprite.Stop()
 			}
 
-			v.iconContainer = container.NewPadded(&image.Image)
+			v.sprite = sprite
+			v.iconContainer = container.NewPadded(&sprite.Image)
 
 			v.container.Add(v.iconContainer)
 		}
@@ -112,10 +125,52 @@ func (v *blockView) setupIconAnimation() {
 	}))
 }
 
+// composeOverBackground draws the icon frames over the block background, the way the BIOS
+// draws icons over the card screen. Transparent pixels show the background, semi-transparent
+// pixels are blended with it.
+func composeOverBackground(animation animatedsprite.Animation, background color.Color) animatedsprite.Animation {
+	frames := make([]image.Image, 0, len(animation.Frames))
+	for _, frame := range animation.Frames {
+		composed := image.NewRGBA(frame.Bounds())
+		draw.Draw(composed, composed.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+		draw.Draw(composed, composed.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		frames = append(frames, composed)
+	}
+
+	animation.Frames = frames
+	return animation
+}
+
 func (v *blockView) Tapped(ev *fyne.PointEvent) {
 	v.model.ToggleSelect()
 }
 
+// TappedSecondary shows the context menu of the block.
+func (v *blockView) TappedSecondary(ev *fyne.PointEvent) {
+	items := []*fyne.MenuItem{}
+
+	allocated, _ := v.model.Allocated.Get()
+	if allocated && v.model.OnExportIcon != nil {
+		items = append(items,
+			fyne.NewMenuItem("Export icon as PNG", func() { v.model.OnExportIcon(memcard.IconExportPNG) }),
+			fyne.NewMenuItem("Export icon as animated GIF", func() { v.model.OnExportIcon(memcard.IconExportGIF) }),
+			fyne.NewMenuItem("Export icon sprite sheet", func() { v.model.OnExportIcon(memcard.IconExportSpriteSheet) }),
+		)
+	}
+	if allocated && v.model.OnImportIcon != nil {
+		items = append(items, fyne.NewMenuItem("Import icon...", v.model.OnImportIcon))
+	}
+	if v.model.OnExportContactSheet != nil {
+		items = append(items, fyne.NewMenuItem("Export card contact sheet", v.model.OnExportContactSheet))
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	windowCanvas := fyne.CurrentApp().Driver().CanvasForObject(v)
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), windowCanvas, ev.AbsolutePosition)
+}
+
 func (v *blockView) CreateRenderer() fyne.WidgetRenderer {
 	return widget.NewSimpleRenderer(v.container)
 }
@@ -39,11 +39,39 @@ func NewBlockGridContainerViewModel(cardId memcard.MemoryCardID, blockBindings b
 	return vm
 }
 
+// SetOnExportIcon sets the action of the export icon entries of the block context menus.
+func (c *ContainerViewModel) SetOnExportIcon(callback func(cardID memcard.MemoryCardID, blockIndex int, format memcard.IconExportFormat)) {
+	for _, block := range c.Blocks {
+		block.OnExportIcon = func(format memcard.IconExportFormat) {
+			callback(c.cardId, block.Index, format)
+		}
+	}
+}
+
+// SetOnImportIcon sets the action of the import icon entry of the block context menus.
+func (c *ContainerViewModel) SetOnImportIcon(callback func(cardID memcard.MemoryCardID, blockIndex int)) {
+	for _, block := range c.Blocks {
+		block.OnImportIcon = func() {
+			callback(c.cardId, block.Index)
+		}
+	}
+}
+
+// SetOnExportContactSheet sets the action of the export contact sheet entry of the block context menus.
+func (c *ContainerViewModel) SetOnExportContactSheet(callback func(cardID memcard.MemoryCardID)) {
+	for _, block := range c.Blocks {
+		block.OnExportContactSheet = func() {
+			callback(c.cardId)
+		}
+	}
+}
+
 func (c *ContainerViewModel) Refresh() {
 
 	// Update the block views based on the current state of the blocks list
 	for i := range len(c.Blocks) {
 		c.Blocks[i].Allocated.Set(false)
+		c.Blocks[i].Remapped.Set(false)
 		c.Blocks[i].Animation.Set(animatedsprite.Animation{})
 		c.Blocks[i].GameTitle.Set("")
 	}
@@ -69,20 +97,23 @@ func (c *ContainerViewModel) Refresh() {
 			continue
 		}
 
-		if blockItem != nil {
-			idx := block.Index
-			animation := block.Animation
-
-			c.Blocks[idx].Animation.Set(animation)
-			c.Blocks[idx].Allocated.Set(true)
-
+		if block.Index < 0 || block.Index >= len(c.Blocks) || !block.Used {
+			continue
 		}
+
+		idx := block.Index
+		c.Blocks[idx].Animation.Set(block.Animation)
+		c.Blocks[idx].GameTitle.Set(block.Title)
+		c.Blocks[idx].Allocated.Set(true)
+		c.Blocks[idx].Remapped.Set(block.Remapped)
 	}
 }
 
+// Item is bound to the block view at the physical block index Index.
 type Item struct {
 	Index     int
 	Title     string
 	Animation animatedsprite.Animation
 	Used      bool
+	Remapped  bool
 }
@@ -47,6 +47,21 @@ func (b *Container) SetOnBlockSelected(callback func(cardID memcard.MemoryCardID
 	b.vm.OnBlockSelected = callback
 }
 
+// SetOnExportIcon sets the action that exports the icon of a save from the block context menu.
+func (b *Container) SetOnExportIcon(callback func(cardID memcard.MemoryCardID, blockIndex int, format memcard.IconExportFormat)) {
+	b.vm.SetOnExportIcon(callback)
+}
+
+// SetOnImportIcon sets the action that replaces the icon of a save from the block context menu.
+func (b *Container) SetOnImportIcon(callback func(cardID memcard.MemoryCardID, blockIndex int)) {
+	b.vm.SetOnImportIcon(callback)
+}
+
+// SetOnExportContactSheet sets the action that exports the icons of the whole card from the block context menu.
+func (b *Container) SetOnExportContactSheet(callback func(cardID memcard.MemoryCardID)) {
+	b.vm.SetOnExportContactSheet(callback)
+}
+
 func (b *Container) Refresh() {
 	b.BaseWidget.Refresh()
 	b.vm.Refresh()
@@ -0,0 +1,165 @@
+package iconeditor
+
+import (
+	"fmt"
+	"image"
+	"slices"
+
+	"com.yv35.memcard/internal/memcard"
+	animatedsprite "com.yv35.memcard/internal/ui/animated-sprite"
+)
+
+const (
+	MaxFrames   = 3
+	PaletteSize = 16
+	MaxChannel  = 0x1F // 5-bit color channel
+
+	stpBit = 0x8000
+)
+
+// ViewModel holds the icon while it is edited. Changes are only written to the card by Save.
+type ViewModel struct {
+	icon          memcard.Icon
+	selectedFrame int
+	selectedColor int
+	save          func(icon memcard.Icon) error
+
+	// OnChanged is called after the icon, the selected frame or the selected color changed
+	OnChanged func()
+	// OnFrameCountChanged is called after a frame was added or removed
+	OnFrameCountChanged func()
+}
+
+// NewViewModel creates a view model editing a copy of icon. save writes the edited icon.
+func NewViewModel(icon memcard.Icon, save func(icon memcard.Icon) error) *ViewModel {
+	icon.Frames = append([]memcard.IconBitmapFrame{}, icon.Frames...)
+	if len(icon.Frames) == 0 {
+		icon.Frames = []memcard.IconBitmapFrame{{}}
+	}
+
+	return &ViewModel{
+		icon:          icon,
+		selectedColor: 1,
+		save:          save,
+	}
+}
+
+func (vm *ViewModel) FrameCount() int {
+	return len(vm.icon.Frames)
+}
+
+func (vm *ViewModel) SelectedFrame() int {
+	return vm.selectedFrame
+}
+
+func (vm *ViewModel) SelectedColor() int {
+	return vm.selectedColor
+}
+
+// PaletteColor returns the 15-bit BGR palette color at index.
+func (vm *ViewModel) PaletteColor(index int) uint16 {
+	return vm.icon.Palette[index]
+}
+
+// Frame returns the selected frame as image.
+func (vm *ViewModel) Frame() image.Image {
+	return vm.icon.Frames[vm.selectedFrame].ToImage(vm.icon.Palette)
+}
+
+// Animation returns the animation of all frames with the BIOS frame delays.
+func (vm *ViewModel) Animation() animatedsprite.Animation {
+	frames := []image.Image{}
+	for _, frame := range vm.icon.Images(memcard.STPOpaque) {
+		frames = append(frames, frame)
+	}
+
+	return animatedsprite.NewAnimation(frames, memcard.IconFrameDelay(len(frames)))
+}
+
+func (vm *ViewModel) SelectFrame(frame int) {
+	if frame < 0 || frame >= len(vm.icon.Frames) {
+		return
+	}
+	vm.selectedFrame = frame
+	vm.changed()
+}
+
+func (vm *ViewModel) SelectColor(index int) {
+	if index < 0 || index >= PaletteSize {
+		return
+	}
+	vm.selectedColor = index
+	vm.changed()
+}
+
+// Paint sets the pixel of the selected frame to the selected color.
+func (vm *ViewModel) Paint(x, y int) {
+	frame := &vm.icon.Frames[vm.selectedFrame]
+	if x < 0 || x >= memcard.IconSize || y < 0 || y >= memcard.IconSize || int(frame.PixelAt(x, y)) == vm.selectedColor {
+		return
+	}
+	frame.SetPixelAt(x, y, byte(vm.selectedColor))
+	vm.changed()
+}
+
+// Channels returns the 5-bit red, green and blue channels and the STP bit of the selected color.
+func (vm *ViewModel) Channels() (r, g, b int, stp bool) {
+	c := vm.icon.Palette[vm.selectedColor]
+	return int(c & MaxChannel), int(c >> 5 & MaxChannel), int(c >> 10 & MaxChannel), c&stpBit != 0
+}
+
+// SetChannels sets the selected color from 5-bit channels. Black without the STP bit is transparent.
+func (vm *ViewModel) SetChannels(r, g, b int, stp bool) {
+	c := uint16(r&MaxChannel) | uint16(g&MaxChannel)<<5 | uint16(b&MaxChannel)<<10
+	if stp {
+		c |= stpBit
+	}
+	if vm.icon.Palette[vm.selectedColor] == c {
+		return
+	}
+	vm.icon.Palette[vm.selectedColor] = c
+	vm.changed()
+}
+
+// AddFrame adds a copy of the selected frame after it.
+func (vm *ViewModel) AddFrame() error {
+	if len(vm.icon.Frames) >= MaxFrames {
+		return fmt.Errorf("icons have at most %d frames", MaxFrames)
+	}
+
+	frame := vm.icon.Frames[vm.selectedFrame]
+	vm.selectedFrame++
+	vm.icon.Frames = slices.Insert(vm.icon.Frames, vm.selectedFrame, frame)
+	vm.frameCountChanged()
+	return nil
+}
+
+// RemoveFrame removes the selected frame.
+func (vm *ViewModel) RemoveFrame() error {
+	if len(vm.icon.Frames) <= 1 {
+		return fmt.Errorf("icons have at least one frame")
+	}
+
+	vm.icon.Frames = slices.Delete(vm.icon.Frames, vm.selectedFrame, vm.selectedFrame+1)
+	vm.selectedFrame = min(vm.selectedFrame, len(vm.icon.Frames)-1)
+	vm.frameCountChanged()
+	return nil
+}
+
+// Save writes the edited icon.
+func (vm *ViewModel) Save() error {
+	return vm.save(vm.icon)
+}
+
+func (vm *ViewModel) changed() {
+	if vm.OnChanged != nil {
+		vm.OnChanged()
+	}
+}
+
+func (vm *ViewModel) frameCountChanged() {
+	if vm.OnFrameCountChanged != nil {
+		vm.OnFrameCountChanged()
+	}
+	vm.changed()
+}
@@ -0,0 +1,267 @@
+package iconeditor
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"com.yv35.memcard/internal/memcard"
+	animatedsprite "com.yv35.memcard/internal/ui/animated-sprite"
+	"com.yv35.memcard/internal/ui/blocks"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	// Transparent pixels are shown on a checkerboard
+	CHECKER_LIGHT_COLOR   = color.NRGBA{R: 220, G: 220, B: 220, A: 255}
+	CHECKER_DARK_COLOR    = color.NRGBA{R: 180, G: 180, B: 180, A: 255}
+	SELECTED_SWATCH_COLOR = color.NRGBA{R: 200, G: 100, B: 100, A: 255}
+	SWATCH_BORDER_COLOR   = color.NRGBA{R: 0, G: 0, B: 0, A: 60}
+	PIXEL_ZOOM            = float32(20)
+	SWATCH_SIZE           = float32(28)
+	PREVIEW_SIZE          = float32(64)
+)
+
+// ShowEditor shows the icon editor in a dialog. save is called with the edited icon when the user saves.
+func ShowEditor(icon memcard.Icon, save func(icon memcard.Icon) error, window fyne.Window) {
+	vm := NewViewModel(icon, save)
+
+	pixels := newPixelGrid(vm)
+
+	swatches := container.NewGridWithColumns(PaletteSize / 2)
+	swatchViews := []*swatch{}
+	for i := range PaletteSize {
+		s := newSwatch(vm, i)
+		swatchViews = append(swatchViews, s)
+		swatches.Add(s)
+	}
+
+	// Channel sliders of the selected color, updating guards against writing the old
+	// channels into a newly selected color
+	updating := false
+	sliders := [3]*widget.Slider{}
+	sliderLabels := [3]*widget.Label{}
+	stp := widget.NewCheck("STP (black with STP is opaque)", nil)
+	setChannels := func() {
+		if updating {
+			return
+		}
+		vm.SetChannels(int(sliders[0].Value), int(sliders[1].Value), int(sliders[2].Value), stp.Checked)
+	}
+	for i := range sliders {
+		sliders[i] = widget.NewSlider(0, MaxChannel)
+		sliders[i].Step = 1
+		sliders[i].OnChanged = func(float64) { setChannels() }
+		sliderLabels[i] = widget.NewLabel("")
+	}
+	stp.OnChanged = func(bool) { setChannels() }
+
+	channelNames := [3]string{"Red", "Green", "Blue"}
+	channels := container.NewGridWithColumns(2)
+	for i := range sliders {
+		channels.Add(sliderLabels[i])
+		channels.Add(sliders[i])
+	}
+
+	frames := widget.NewRadioGroup(nil, nil)
+	frames.Horizontal = true
+	frames.Required = true
+	frames.OnChanged = func(selected string) {
+		for i, option := range frames.Options {
+			if option == selected {
+				vm.SelectFrame(i)
+			}
+		}
+	}
+	updateFrameOptions := func() {
+		options := []string{}
+		for i := range vm.FrameCount() {
+			options = append(options, fmt.Sprintf("Frame %d", i+1))
+		}
+		frames.Options = options
+		frames.Selected = options[vm.SelectedFrame()]
+		frames.Refresh()
+	}
+
+	btnAddFrame := widget.NewButton("Add frame", func() {
+		if err := vm.AddFrame(); err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+	btnRemoveFrame := widget.NewButton("Remove frame", func() {
+		if err := vm.RemoveFrame(); err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+
+	// The preview sprite is replaced when the frame count changes, its frame delay depends on it.
+	// Other changes only replace its frames, so the preview keeps animating while painting
+	preview := container.NewStack()
+	var sprite *animatedsprite.AnimatedSprite
+	replaceSprite := func() {
+		if sprite != nil {
+			sprite.Stop()
+		}
+		sprite = animatedsprite.NewAnimatedSprite(vm.Animation())
+		sprite.Image.SetMinSize(fyne.NewSize(PREVIEW_SIZE, PREVIEW_SIZE))
+
+		background := canvas.NewRectangle(blocks.FILL_COLOR)
+		preview.Objects = []fyne.CanvasObject{background, &sprite.Image}
+		preview.Refresh()
+	}
+
+	vm.OnFrameCountChanged = func() {
+		updateFrameOptions()
+		replaceSprite()
+	}
+	vm.OnChanged = func() {
+		pixels.Refresh()
+		for _, s := range swatchViews {
+			s.Refresh()
+		}
+
+		updating = true
+		r, g, b, stpSet := vm.Channels()
+		for i, value := range []int{r, g, b} {
+			sliders[i].SetValue(float64(value))
+			sliderLabels[i].SetText(fmt.Sprintf("%s %d", channelNames[i], value))
+		}
+		stp.SetChecked(stpSet)
+		updating = false
+
+		sprite.SetFrames(vm.Animation().Frames)
+	}
+
+	updateFrameOptions()
+	replaceSprite()
+	vm.OnChanged()
+
+	content := container.NewBorder(
+		nil,
+		container.NewVBox(
+			widget.NewLabel("Palette (black without STP is transparent)"),
+			swatches,
+			channels,
+			stp,
+		),
+		nil,
+		container.NewVBox(
+			widget.NewLabel("Preview"),
+			container.NewCenter(preview),
+			frames,
+			btnAddFrame,
+			btnRemoveFrame,
+		),
+		container.NewCenter(pixels),
+	)
+
+	editor := dialog.NewCustomConfirm("Edit icon", "Save", "Cancel", content, func(confirmed bool) {
+		sprite.Stop()
+		if !confirmed {
+			return
+		}
+		if err := vm.Save(); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+	editor.Show()
+}
+
+// pixelGrid shows the selected frame zoomed. Tapping or dragging paints with the selected color.
+type pixelGrid struct {
+	widget.BaseWidget
+	vm    *ViewModel
+	image *canvas.Image
+}
+
+func newPixelGrid(vm *ViewModel) *pixelGrid {
+	size := PIXEL_ZOOM * memcard.IconSize
+	img := canvas.NewImageFromImage(nil)
+	img.ScaleMode = canvas.ImageScalePixels
+	img.FillMode = canvas.ImageFillStretch
+	img.SetMinSize(fyne.NewSize(size, size))
+
+	grid := &pixelGrid{vm: vm, image: img}
+	grid.ExtendBaseWidget(grid)
+	return grid
+}
+
+func (g *pixelGrid) Refresh() {
+	frame := g.vm.Frame()
+	checkered := image.NewNRGBA(frame.Bounds())
+	for y := range memcard.IconSize {
+		for x := range memcard.IconSize {
+			checkered.Set(x, y, CHECKER_LIGHT_COLOR)
+			if (x+y)%2 == 1 {
+				checkered.Set(x, y, CHECKER_DARK_COLOR)
+			}
+		}
+	}
+	draw.Draw(checkered, checkered.Bounds(), frame, image.Point{}, draw.Over)
+
+	g.image.Image = checkered
+	g.image.Refresh()
+}
+
+func (g *pixelGrid) paintAt(pos fyne.Position) {
+	size := g.Size()
+	if size.Width <= 0 || size.Height <= 0 {
+		return
+	}
+	g.vm.Paint(int(pos.X/size.Width*memcard.IconSize), int(pos.Y/size.Height*memcard.IconSize))
+}
+
+func (g *pixelGrid) Tapped(ev *fyne.PointEvent) {
+	g.paintAt(ev.Position)
+}
+
+func (g *pixelGrid) Dragged(ev *fyne.DragEvent) {
+	g.paintAt(ev.Position)
+}
+
+func (g *pixelGrid) DragEnd() {}
+
+func (g *pixelGrid) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(g.image)
+}
+
+// swatch shows a palette color, tapping it selects the color for painting.
+type swatch struct {
+	widget.BaseWidget
+	vm    *ViewModel
+	index int
+	rect  *canvas.Rectangle
+}
+
+func newSwatch(vm *ViewModel, index int) *swatch {
+	rect := canvas.NewRectangle(color.Transparent)
+	rect.SetMinSize(fyne.NewSize(SWATCH_SIZE, SWATCH_SIZE))
+	rect.StrokeWidth = 2
+
+	s := &swatch{vm: vm, index: index, rect: rect}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+func (s *swatch) Refresh() {
+	s.rect.FillColor = memcard.IconColorToNRGBA(s.vm.PaletteColor(s.index), memcard.STPOpaque)
+	s.rect.StrokeColor = SWATCH_BORDER_COLOR
+	if s.vm.SelectedColor() == s.index {
+		s.rect.StrokeColor = SELECTED_SWATCH_COLOR
+	}
+	s.rect.Refresh()
+}
+
+func (s *swatch) Tapped(*fyne.PointEvent) {
+	s.vm.SelectColor(s.index)
+}
+
+func (s *swatch) CreateRenderer() fyne.WidgetRenderer {
+	background := canvas.NewRectangle(CHECKER_DARK_COLOR)
+	return widget.NewSimpleRenderer(container.NewStack(background, s.rect))
+}
@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"com.yv35.memcard/internal/memcard"
 	_ui_blocks "com.yv35.memcard/internal/ui/blocks"
@@ -13,8 +16,13 @@ import (
 
 const NoBlockSelected = -1
 
+// iconExportScale is the scale of exported icons, 16x16 icons are exported as 64x64 images.
+const iconExportScale = 4
+
 type ManagerWindowViewModel struct {
 	window fyne.Window
+	codecs *memcard.CodecRegistry
+	games  *memcard.GameDatabase
 
 	selection *_ui_blocks.SelectionViewModel
 
@@ -30,9 +38,11 @@ type ManagerWindowViewModel struct {
 	rightMemoryCardPath string
 }
 
-func NewManagerWindowViewModel(window fyne.Window) *ManagerWindowViewModel {
+func NewManagerWindowViewModel(window fyne.Window, codecs *memcard.CodecRegistry, games *memcard.GameDatabase) *ManagerWindowViewModel {
 	win := &ManagerWindowViewModel{
 		window:                window,
+		codecs:                codecs,
+		games:                 games,
 		blocksLeft:            binding.NewUntypedList(),
 		blocksRight:           binding.NewUntypedList(),
 		selectedSaveGameTitle: binding.NewString(),
@@ -49,19 +59,17 @@ func (vm *ManagerWindowViewModel) SelectionViewModel() *_ui_blocks.SelectionView
 }
 
 func (vm *ManagerWindowViewModel) LoadMemoryCardImage(path string, memoryCardId memcard.MemoryCardID) {
-	// Open the memory card file
-	card, err := memcard.Open(path)
+	// Open the memory card file in any of the registered formats
+	card, err := vm.codecs.Open(path)
 	if err != nil {
 		dialog.ShowError(err, vm.window)
 		return
 	}
 
 	fmt.Printf("Loaded memory card: %+v\n", card)
-	blocks, err := card.ListBlocks()
-	if err != nil {
-		dialog.ShowError(err, vm.window)
-		return
-	}
+
+	// The blocks and the footer describe games from the same database
+	card.SetGameDatabase(vm.games)
 
 	var blockBindingList binding.UntypedList
 	if memoryCardId == memcard.MemoryCardLeft {
@@ -74,20 +82,30 @@ func (vm *ManagerWindowViewModel) LoadMemoryCardImage(path string, memoryCardId
 		vm.rightMemoryCardPath = path
 	}
 
-	bindings := []any{}
-	for idx, block := range blocks {
+	blockBindingList.Set(blockItems(card))
+}
 
-		blockItem := _ui_blocks.Item{
-			Index:     idx,
-			Title:     block.Title,
-			Animation: block.Animation,
-			Used:      block.Title != "",
+// blockItems binds every physical slot of the card to a block item, so each save shows up
+// in the grid cell of its block. Linked blocks show the icon of the file they belong to.
+func blockItems(card *memcard.MemoryCard) []any {
+	items := []any{}
+	for _, slot := range card.ListSlots() {
+		item := _ui_blocks.Item{
+			Index:    slot.Index,
+			Used:     slot.InUse(),
+			Remapped: slot.InUse() && slot.Remapped,
 		}
 
-		bindings = append(bindings, blockItem)
-	}
+		if item.Used && slot.FirstBlock != memcard.NoBlockIndex {
+			item.Title = slot.Title
+			if block, err := card.GetBlock(slot.FirstBlock); err == nil && block != nil {
+				item.Animation = block.Animation
+			}
+		}
 
-	blockBindingList.Set(bindings)
+		items = append(items, item)
+	}
+	return items
 }
 
 func (vm *ManagerWindowViewModel) getMemoryCardById(cardId memcard.MemoryCardID) *memcard.MemoryCard {
@@ -130,15 +148,15 @@ func (vm *ManagerWindowViewModel) CopyCommand(sourceCardId memcard.MemoryCardID,
 		return fmt.Errorf("cannot copy block: target memory card \"%s\" is not loaded", targetCardId)
 	}
 
-	// Copy the block to the target card
-	if err := sourceCard.CopyBlockTo(blockIndex, targetCard); err != nil {
-		return fmt.Errorf("failed to copy block: %w", err)
-	}
-
-	// Write the target card to disk
-	targetCardPath := vm.GetMemoryCardPathById(targetCardId)
-	if err := targetCard.Write(targetCardPath); err != nil {
-		return fmt.Errorf("failed to write target memory card: %w", err)
+	// Copy the block to the target card and write the target card to disk
+	err := vm.changeCard(targetCardId, targetCard, func() error {
+		if err := sourceCard.CopyBlockTo(blockIndex, targetCard); err != nil {
+			return fmt.Errorf("failed to copy block: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Refresh the target card bindings to show the new block
@@ -160,50 +178,222 @@ func (vm *ManagerWindowViewModel) DeleteCommand(sourceCardId memcard.MemoryCardI
 		return fmt.Errorf("cannot delete block without loading a memory card \"%s\"", sourceCardId)
 	}
 
-	if err := card.DeleteBlockFrom(blockIndex); err != nil {
+	err := vm.changeCard(sourceCardId, card, func() error {
+		return card.DeleteBlockFrom(blockIndex)
+	})
+	if err != nil {
 		return err
 	}
 
-	vm.RefreshCardBindings(sourceCardId)
+	// Refresh the card bindings only once the card was written, so they always show the file
+	if err := vm.RefreshCardBindings(sourceCardId); err != nil {
+		return fmt.Errorf("failed to refresh card bindings: %w", err)
+	}
 
-	return card.Write(vm.GetMemoryCardPathById(sourceCardId))
+	return nil
 }
 
-func (vm *ManagerWindowViewModel) RefreshCardBindings(sourceCardId memcard.MemoryCardID) error {
+// CompactCommand moves the files of the memory card together, so every file occupies
+// contiguous blocks and the free blocks are packed at the end of the card.
+func (vm *ManagerWindowViewModel) CompactCommand(cardId memcard.MemoryCardID) error {
+	if cardId == "" {
+		return fmt.Errorf("cannot compact without selecting a memory card")
+	}
 
-	card := vm.getMemoryCardById(sourceCardId)
+	card := vm.getMemoryCardById(cardId)
 	if card == nil {
-		return fmt.Errorf("cannot refresh bindings without loading a memory card \"%s\"", sourceCardId)
+		return fmt.Errorf("cannot compact without loading a memory card \"%s\"", cardId)
 	}
 
-	// TODO: Have a method that refresh the bindings for all blocks based on the changed memory card
-	var blockBindingList binding.UntypedList
-	if sourceCardId == memcard.MemoryCardLeft {
-		blockBindingList = vm.blocksLeft
-	} else {
-		blockBindingList = vm.blocksRight
+	err := vm.changeCard(cardId, card, func() error {
+		if err := card.Compact(); err != nil {
+			return fmt.Errorf("failed to compact memory card: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Blocks have moved, so the selected block index no longer points to the same save
+	vm.selection.ClearSelection()
+
+	return vm.RefreshCardBindings(cardId)
+}
+
+// ExportIconCommand writes the icon of the save the block belongs to into a file.
+// The extension of the format is added to paths without extension.
+func (vm *ManagerWindowViewModel) ExportIconCommand(cardId memcard.MemoryCardID, blockIndex int, format memcard.IconExportFormat, path string) error {
+	card := vm.getMemoryCardById(cardId)
+	if card == nil {
+		return fmt.Errorf("cannot export icon without loading a memory card \"%s\"", cardId)
+	}
+
+	var buf bytes.Buffer
+	if err := card.ExportIcon(&buf, blockIndex, format, iconExportScale); err != nil {
+		return fmt.Errorf("failed to export icon: %w", err)
 	}
+	return writeExportFile(path, format.Extension(), buf.Bytes())
+}
 
-	blocks, err := card.ListBlocks()
+// ExportContactSheetCommand writes the icons of all blocks of the card into a PNG file.
+func (vm *ManagerWindowViewModel) ExportContactSheetCommand(cardId memcard.MemoryCardID, path string) error {
+	card := vm.getMemoryCardById(cardId)
+	if card == nil {
+		return fmt.Errorf("cannot export contact sheet without loading a memory card \"%s\"", cardId)
+	}
+
+	var buf bytes.Buffer
+	if err := card.ExportContactSheet(&buf, iconExportScale); err != nil {
+		return fmt.Errorf("failed to export contact sheet: %w", err)
+	}
+	return writeExportFile(path, memcard.IconExportPNG.Extension(), buf.Bytes())
+}
+
+// ImportIconCommand replaces the icon of the save the block belongs to with a PNG or GIF file
+// and writes the card.
+func (vm *ManagerWindowViewModel) ImportIconCommand(cardId memcard.MemoryCardID, blockIndex int, path string) error {
+	card := vm.getMemoryCardById(cardId)
+	if card == nil {
+		return fmt.Errorf("cannot import icon without loading a memory card \"%s\"", cardId)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	err = vm.changeCard(cardId, card, func() error {
+		if err := card.ImportIcon(blockIndex, file); err != nil {
+			return fmt.Errorf("failed to import icon: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return vm.RefreshCardBindings(cardId)
+}
+
+// IconOf returns the icon of the save the block belongs to.
+func (vm *ManagerWindowViewModel) IconOf(cardId memcard.MemoryCardID, blockIndex int) (memcard.Icon, error) {
+	card := vm.getMemoryCardById(cardId)
+	if card == nil {
+		return memcard.Icon{}, fmt.Errorf("cannot edit icon without loading a memory card \"%s\"", cardId)
+	}
+
+	if blockIndex < 0 || blockIndex >= memcard.NumBlocks {
+		return memcard.Icon{}, fmt.Errorf("cannot edit icon without selecting a block")
+	}
+
+	return card.Icon(blockIndex)
+}
+
+// SaveIconCommand replaces the icon of the save the block belongs to and writes the card.
+func (vm *ManagerWindowViewModel) SaveIconCommand(cardId memcard.MemoryCardID, blockIndex int, icon memcard.Icon) error {
+	card := vm.getMemoryCardById(cardId)
+	if card == nil {
+		return fmt.Errorf("cannot save icon without loading a memory card \"%s\"", cardId)
+	}
+
+	err := vm.changeCard(cardId, card, func() error {
+		if err := card.WriteIcon(blockIndex, icon); err != nil {
+			return fmt.Errorf("failed to save icon: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		dialog.ShowError(err, vm.window)
 		return err
 	}
 
-	bindings := []any{}
-	for idx, block := range blocks {
+	return vm.RefreshCardBindings(cardId)
+}
 
-		blockItem := _ui_blocks.Item{
-			Index:     idx,
-			Title:     block.Title,
-			Animation: block.Animation,
-			Used:      block.Title != "",
+// SaveTitleOf returns the title of the save the block belongs to, "" for free blocks.
+func (vm *ManagerWindowViewModel) SaveTitleOf(cardId memcard.MemoryCardID, blockIndex int) string {
+	card := vm.getMemoryCardById(cardId)
+	if card == nil || blockIndex < 0 || blockIndex >= memcard.NumBlocks {
+		return ""
+	}
+
+	slot := card.ListSlots()[blockIndex]
+	if !slot.InUse() {
+		return ""
+	}
+	return slot.Title
+}
+
+// RenameCommand sets the title of the save the block belongs to and writes the card.
+// With fullWidth, ASCII characters are converted to full-width characters.
+func (vm *ManagerWindowViewModel) RenameCommand(cardId memcard.MemoryCardID, blockIndex int, title string, fullWidth bool) error {
+	card := vm.getMemoryCardById(cardId)
+	if card == nil {
+		return fmt.Errorf("cannot rename save without loading a memory card \"%s\"", cardId)
+	}
+
+	err := vm.changeCard(cardId, card, func() error {
+		if err := card.RenameSave(blockIndex, title, fullWidth); err != nil {
+			return fmt.Errorf("failed to rename save: %w", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := vm.RefreshCardBindings(cardId); err != nil {
+		return err
+	}
+
+	vm.HandleBlockSelectionChanged(cardId, blockIndex)
+	return nil
+}
 
-		bindings = append(bindings, blockItem)
+// changeCard applies change to the card and writes it. The card is restored when the change or
+// the write fails, so the card in memory keeps matching its file.
+func (vm *ManagerWindowViewModel) changeCard(cardId memcard.MemoryCardID, card *memcard.MemoryCard, change func() error) error {
+	previous := card.Clone()
+	if err := change(); err != nil {
+		*card = *previous
+		return err
 	}
 
-	blockBindingList.Set(bindings)
+	if err := card.Write(vm.GetMemoryCardPathById(cardId)); err != nil {
+		*card = *previous
+		return fmt.Errorf("failed to write memory card: %w", err)
+	}
+	return nil
+}
+
+func writeExportFile(path, extension string, data []byte) error {
+	if filepath.Ext(path) == "" {
+		path += extension
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (vm *ManagerWindowViewModel) RefreshCardBindings(sourceCardId memcard.MemoryCardID) error {
+
+	card := vm.getMemoryCardById(sourceCardId)
+	if card == nil {
+		return fmt.Errorf("cannot refresh bindings without loading a memory card \"%s\"", sourceCardId)
+	}
+
+	// TODO: Have a method that refresh the bindings for all blocks based on the changed memory card
+	var blockBindingList binding.UntypedList
+	if sourceCardId == memcard.MemoryCardLeft {
+		blockBindingList = vm.blocksLeft
+	} else {
+		blockBindingList = vm.blocksRight
+	}
+
+	blockBindingList.Set(blockItems(card))
 	return nil
 }
 
@@ -228,13 +418,26 @@ func (vm *ManagerWindowViewModel) HandleBlockSelectionChanged(cardId memcard.Mem
 		return
 	}
 
-	blockItem, err := card.GetBlock(blockIndex)
-	if err != nil || blockItem == nil {
+	if blockIndex < 0 || blockIndex >= memcard.NumBlocks {
+		vm.selectedSaveGameTitle.Set("")
+		return
+	}
+
+	// Linked blocks show the title of the file they belong to
+	slot := card.ListSlots()[blockIndex]
+	if !slot.InUse() {
 		vm.selectedSaveGameTitle.Set("")
 		return
 	}
 
-	vm.selectedSaveGameTitle.Set(blockItem.Title)
+	title := slot.Title
+	if game, found := vm.games.Lookup(slot.ProductCode); found {
+		title = fmt.Sprintf("%s - %s", title, game)
+	}
+	if slot.Remapped {
+		title += " (stored in remapped frames)"
+	}
+	vm.selectedSaveGameTitle.Set(title)
 
 }
 
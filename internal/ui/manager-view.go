@@ -7,12 +7,14 @@ import (
 	"com.yv35.memcard/internal/ui/blocks"
 	"com.yv35.memcard/internal/ui/blockstats"
 	"com.yv35.memcard/internal/ui/filepicker"
+	iconeditor "com.yv35.memcard/internal/ui/icon-editor"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -21,14 +23,15 @@ type ManagerWindowView struct {
 	container *fyne.Container
 }
 
-func NewManagerWindowView(window fyne.Window) *ManagerWindowView {
-	model := NewManagerWindowViewModel(window)
+func NewManagerWindowView(window fyne.Window, codecs *memcard.CodecRegistry, games *memcard.GameDatabase) *ManagerWindowView {
+	model := NewManagerWindowViewModel(window, codecs, games)
 	view := &ManagerWindowView{
 		model: model,
 	}
 
 	leftMemoryCardView := blocks.NewContainer(memcard.MemoryCardLeft, model.blocksLeft, model.selection)
 	leftMemoryCardView.SetOnBlockSelected(model.HandleBlockSelectionChanged)
+	setupIconActions(window, model, leftMemoryCardView)
 
 	leftMemoryCardFilePicker := filepicker.NewFilePicker(&window)
 	leftMemoryCardFilePicker.SetOnChanged(func(filePath string) {
@@ -46,6 +49,7 @@ func NewManagerWindowView(window fyne.Window) *ManagerWindowView {
 
 	rightMemoryCardView := blocks.NewContainer(memcard.MemoryCardRight, model.blocksRight, model.selection)
 	rightMemoryCardView.SetOnBlockSelected(model.HandleBlockSelectionChanged)
+	setupIconActions(window, model, rightMemoryCardView)
 
 	rightMemoryCardFilePicker := filepicker.NewFilePicker(&window)
 	rightMemoryCardFilePicker.SetOnChanged(func(filePath string) {
@@ -74,9 +78,30 @@ func NewManagerWindowView(window fyne.Window) *ManagerWindowView {
 		}
 	})
 
+	btnCompact := widget.NewButton("Compact card", func() {
+		if err := model.CompactCommand(model.SelectedCard()); err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+
+	btnEditIcon := widget.NewButton("Edit icon", func() {
+		cardId, blockIndex := model.SelectedCard(), model.SelectedBlockIndex()
+		icon, err := model.IconOf(cardId, blockIndex)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		iconeditor.ShowEditor(icon, func(icon memcard.Icon) error {
+			return model.SaveIconCommand(cardId, blockIndex, icon)
+		}, window)
+	})
+
 	buttons.Add(layout.NewSpacer())
 	buttons.Add(btnCopy)
 	buttons.Add(btnDelete)
+	buttons.Add(btnCompact)
+	buttons.Add(btnEditIcon)
 	buttons.Add(layout.NewSpacer())
 
 	// Create container for the selected save game label (will be populated dynamically)
@@ -87,7 +112,41 @@ func NewManagerWindowView(window fyne.Window) *ManagerWindowView {
 	var labelSelectedSaveGame *widget.Label
 
 	// Update label text and style based on selection state
-	updateSelectedGameLabel := func() {
+	var updateSelectedGameLabel func()
+
+	// Inline rename of the selected save, replacing the label with an entry until saved or canceled
+	showRenameEntry := func() {
+		cardId, blockIndex := model.SelectedCard(), model.SelectedBlockIndex()
+
+		entry := widget.NewEntry()
+		entry.SetText(model.SaveTitleOf(cardId, blockIndex))
+		fullWidth := widget.NewCheck("Full-width", nil)
+		fullWidth.SetChecked(true)
+
+		rename := func() {
+			if err := model.RenameCommand(cardId, blockIndex, entry.Text, fullWidth.Checked); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			updateSelectedGameLabel()
+		}
+		entry.OnSubmitted = func(string) { rename() }
+
+		selectedSaveGameContainer.RemoveAll()
+		selectedSaveGameContainer.Add(container.NewBorder(
+			nil, nil, nil,
+			container.NewHBox(
+				fullWidth,
+				widget.NewButtonWithIcon("", theme.ConfirmIcon(), rename),
+				widget.NewButtonWithIcon("", theme.CancelIcon(), func() { updateSelectedGameLabel() }),
+			),
+			entry,
+		))
+		selectedSaveGameContainer.Refresh()
+		window.Canvas().Focus(entry)
+	}
+
+	updateSelectedGameLabel = func() {
 		text, _ := model.selectedSaveGameTitle.Get()
 		if text == "" {
 			// Show placeholder text when no block is selected (grayed out, not bold)
@@ -104,7 +163,12 @@ func NewManagerWindowView(window fyne.Window) *ManagerWindowView {
 		}
 		// Update the container content
 		selectedSaveGameContainer.RemoveAll()
-		selectedSaveGameContainer.Add(labelSelectedSaveGame)
+		if text == "" {
+			selectedSaveGameContainer.Add(labelSelectedSaveGame)
+		} else {
+			btnRename := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), showRenameEntry)
+			selectedSaveGameContainer.Add(container.NewCenter(container.NewHBox(labelSelectedSaveGame, btnRename)))
+		}
 		selectedSaveGameContainer.Refresh()
 	}
 
@@ -158,6 +222,52 @@ func (v *ManagerWindowView) Container() *fyne.Container {
 	return v.container
 }
 
+// setupIconActions wires the icon entries of the block context menus. The user picks the
+// file, starting in the directory of the memory card.
+func setupIconActions(window fyne.Window, model *ManagerWindowViewModel, cardView *blocks.Container) {
+	exportTo := func(cardId memcard.MemoryCardID, export func(path string) error) {
+		go func() {
+			picker := filepicker.NewFyneFilePickerService(&window)
+			path, err := picker.SaveFile(model.GetMemoryCardPathById(cardId))
+			fyne.Do(func() {
+				// The card is read on the UI thread, where it is changed by the other commands
+				if err == nil && path != "" {
+					err = export(path)
+				}
+				if err != nil {
+					dialog.ShowError(err, window)
+				}
+			})
+		}()
+	}
+
+	cardView.SetOnExportIcon(func(cardId memcard.MemoryCardID, blockIndex int, format memcard.IconExportFormat) {
+		exportTo(cardId, func(path string) error {
+			return model.ExportIconCommand(cardId, blockIndex, format, path)
+		})
+	})
+	cardView.SetOnImportIcon(func(cardId memcard.MemoryCardID, blockIndex int) {
+		go func() {
+			picker := filepicker.NewFyneFilePickerService(&window)
+			path, err := picker.PickFile(model.GetMemoryCardPathById(cardId))
+			fyne.Do(func() {
+				// The card bindings are refreshed on the UI thread
+				if err == nil && path != "" {
+					err = model.ImportIconCommand(cardId, blockIndex, path)
+				}
+				if err != nil {
+					dialog.ShowError(err, window)
+				}
+			})
+		}()
+	})
+	cardView.SetOnExportContactSheet(func(cardId memcard.MemoryCardID) {
+		exportTo(cardId, func(path string) error {
+			return model.ExportContactSheetCommand(cardId, path)
+		})
+	})
+}
+
 // createCardHeader creates a visually appealing header for a memory card section.
 // It includes a styled background, border, and formatted text.
 func createCardHeader(title string) *fyne.Container {
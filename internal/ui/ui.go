@@ -1,9 +1,13 @@
 package ui
 
 import (
+	"fmt"
+
 	"com.yv35.memcard/internal/dig"
+	"com.yv35.memcard/internal/memcard"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/dialog"
 )
 
 func newApp() fyne.App {
@@ -14,13 +18,32 @@ func newWindow(a fyne.App) fyne.Window {
 	return a.NewWindow("PSX Memory Card Manager")
 }
 
+// newGameDatabase creates the game database of the loaded cards with the embedded games,
+// the games of the user database are added by loadUserGameDatabase.
+func newGameDatabase() *memcard.GameDatabase {
+	return memcard.NewGameDatabase()
+}
+
+// loadUserGameDatabase adds the games of the user database. A broken user database is shown
+// once the window is open, the embedded games are used.
+func loadUserGameDatabase(a fyne.App, window fyne.Window, games *memcard.GameDatabase) {
+	if err := games.LoadUserFile(); err != nil {
+		a.Lifecycle().SetOnStarted(func() {
+			dialog.ShowError(fmt.Errorf("failed to load user game database, using the built-in games: %w", err), window)
+		})
+	}
+}
+
 func Start() error {
 
 	dig.Provide(newApp)
 	dig.Provide(newWindow)
+	dig.Provide(memcard.DefaultCodecRegistry)
+	dig.Provide(newGameDatabase)
 	dig.Provide(NewManagerWindowView)
 
-	return dig.Invoke(func(window fyne.Window, view *ManagerWindowView) {
+	return dig.Invoke(func(a fyne.App, window fyne.Window, games *memcard.GameDatabase, view *ManagerWindowView) {
+		loadUserGameDatabase(a, window, games)
 		window.SetContent(view.Container())
 		window.ShowAndRun()
 	})